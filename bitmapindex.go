@@ -0,0 +1,164 @@
+package succincter
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BitmapIndex is an Index that also answers ad-hoc boolean queries over its named
+// predicates, such as "online AND premium AND NOT banned".
+//
+// Query parses the expression and combines the per-predicate bitvectors a word at a
+// time. Operands of AND are evaluated from smallest to largest estimated cardinality
+// and stop early once the intermediate result is empty; negated AND operands are
+// applied with AndNot instead of materializing their complement. Operands of OR are
+// evaluated largest first and stop once every position is set.
+type BitmapIndex[T any] struct {
+	*Index[T]
+}
+
+// NewBitmapIndex returns a BitmapIndex over items with no predicates. Register
+// predicates with Add.
+func NewBitmapIndex[T any](items []T) *BitmapIndex[T] {
+	return &BitmapIndex[T]{Index: NewIndex(items)}
+}
+
+// Query evaluates expr and returns the matching positions as a new Succincter, whose
+// Ones, Select and Positions give the count, k-th match and iteration. Names refer to
+// predicates added with Add or Register. Operators are AND, OR, NOT (or &&, ||, !)
+// with parentheses for grouping.
+func (bx *BitmapIndex[T]) Query(expr string) (*Succincter, error) {
+	node, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if err := bx.checkNames(node); err != nil {
+		return nil, err
+	}
+	return bx.eval(node), nil
+}
+
+func (bx *BitmapIndex[T]) checkNames(node queryNode) error {
+	switch n := node.(type) {
+	case queryName:
+		if _, ok := bx.bitvectors[n.name]; !ok {
+			return fmt.Errorf("succincter: query references unknown predicate %q", n.name)
+		}
+	case queryNot:
+		return bx.checkNames(n.operand)
+	case queryAnd:
+		for _, op := range n.operands {
+			if err := bx.checkNames(op); err != nil {
+				return err
+			}
+		}
+	case queryOr:
+		for _, op := range n.operands {
+			if err := bx.checkNames(op); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// estimate returns an upper bound on the number of positions matching node.
+func (bx *BitmapIndex[T]) estimate(node queryNode) int {
+	switch n := node.(type) {
+	case queryName:
+		return bx.bitvectors[n.name].Ones()
+	case queryNot:
+		// Len minus an upper bound would be a lower bound, so only a plain name,
+		// whose count is exact, tightens the estimate.
+		if name, ok := n.operand.(queryName); ok {
+			return bx.Len() - bx.bitvectors[name.name].Ones()
+		}
+		return bx.Len()
+	case queryAnd:
+		est := bx.Len()
+		for _, op := range n.operands {
+			est = min(est, bx.estimate(op))
+		}
+		return est
+	case queryOr:
+		est := 0
+		for _, op := range n.operands {
+			est += bx.estimate(op)
+		}
+		return min(est, bx.Len())
+	}
+	return bx.Len()
+}
+
+func (bx *BitmapIndex[T]) eval(node queryNode) *Succincter {
+	switch n := node.(type) {
+	case queryName:
+		return bx.bitvectors[n.name]
+	case queryNot:
+		return Not(bx.eval(n.operand))
+	case queryAnd:
+		return bx.evalAnd(n.operands)
+	case queryOr:
+		return bx.evalOr(n.operands)
+	}
+	panic("succincter: unknown query node")
+}
+
+func (bx *BitmapIndex[T]) evalAnd(operands []queryNode) *Succincter {
+	var positive, negated []queryNode
+	for _, op := range operands {
+		if not, ok := op.(queryNot); ok {
+			negated = append(negated, not.operand)
+		} else {
+			positive = append(positive, op)
+		}
+	}
+	bx.sortByEstimate(positive, false)
+	bx.sortByEstimate(negated, true)
+
+	var result *Succincter
+	if len(positive) > 0 {
+		result = bx.eval(positive[0])
+		positive = positive[1:]
+	} else {
+		result = Not(bx.eval(negated[0]))
+		negated = negated[1:]
+	}
+	for _, op := range positive {
+		if result.Ones() == 0 {
+			return result
+		}
+		result = And(result, bx.eval(op))
+	}
+	for _, op := range negated {
+		if result.Ones() == 0 {
+			return result
+		}
+		result = AndNot(result, bx.eval(op))
+	}
+	return result
+}
+
+func (bx *BitmapIndex[T]) evalOr(operands []queryNode) *Succincter {
+	operands = slices.Clone(operands)
+	bx.sortByEstimate(operands, true)
+
+	result := bx.eval(operands[0])
+	for _, op := range operands[1:] {
+		if result.Ones() == bx.Len() {
+			return result
+		}
+		result = Or(result, bx.eval(op))
+	}
+	return result
+}
+
+func (bx *BitmapIndex[T]) sortByEstimate(nodes []queryNode, descending bool) {
+	slices.SortStableFunc(nodes, func(a, b queryNode) int {
+		ea, eb := bx.estimate(a), bx.estimate(b)
+		if descending {
+			ea, eb = eb, ea
+		}
+		return ea - eb
+	})
+}
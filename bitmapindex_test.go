@@ -0,0 +1,137 @@
+package succincter
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testAccount struct {
+	Online, Premium, Banned, Trial bool
+}
+
+func newTestBitmapIndex() (*BitmapIndex[testAccount], []testAccount) {
+	rng := rand.New(rand.NewSource(23))
+	accounts := make([]testAccount, 5000)
+	for i := range accounts {
+		accounts[i] = testAccount{
+			Online:  rng.Intn(100) < 15,
+			Premium: rng.Intn(100) < 10,
+			Banned:  rng.Intn(100) < 2,
+			Trial:   rng.Intn(100) < 30,
+		}
+	}
+	bx := NewBitmapIndex(accounts)
+	bx.Add("online", func(a testAccount) bool { return a.Online })
+	bx.Add("premium", func(a testAccount) bool { return a.Premium })
+	bx.Add("banned", func(a testAccount) bool { return a.Banned })
+	bx.Add("trial", func(a testAccount) bool { return a.Trial })
+	return bx, accounts
+}
+
+func TestBitmapIndexQuery(t *testing.T) {
+	bx, accounts := newTestBitmapIndex()
+
+	tests := []struct {
+		expr string
+		want func(a testAccount) bool
+	}{
+		{"online", func(a testAccount) bool { return a.Online }},
+		{"online AND premium AND NOT banned", func(a testAccount) bool { return a.Online && a.Premium && !a.Banned }},
+		{"online and (premium or trial)", func(a testAccount) bool { return a.Online && (a.Premium || a.Trial) }},
+		{"NOT online", func(a testAccount) bool { return !a.Online }},
+		{"NOT NOT online", func(a testAccount) bool { return a.Online }},
+		{"!banned && !trial", func(a testAccount) bool { return !a.Banned && !a.Trial }},
+		{"premium || banned || trial", func(a testAccount) bool { return a.Premium || a.Banned || a.Trial }},
+		{"online OR premium AND banned", func(a testAccount) bool { return a.Online || (a.Premium && a.Banned) }},
+		{"NOT (online OR premium)", func(a testAccount) bool { return !(a.Online || a.Premium) }},
+		{"premium AND banned AND online AND trial", func(a testAccount) bool { return a.Premium && a.Banned && a.Online && a.Trial }},
+		{"online OR NOT online", func(a testAccount) bool { return true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := bx.Query(tt.expr)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			var want []int
+			for i, a := range accounts {
+				if tt.want(a) {
+					want = append(want, i)
+				}
+			}
+			if got.Ones() != len(want) {
+				t.Fatalf("Ones() = %d; want %d", got.Ones(), len(want))
+			}
+			if positions := slices.Collect(got.Positions()); !slices.Equal(positions, want) {
+				t.Fatal("Positions() differ from brute force")
+			}
+			if len(want) > 0 && got.Select(len(want)) != want[len(want)-1] {
+				t.Errorf("Select(%d) = %d; want %d", len(want), got.Select(len(want)), want[len(want)-1])
+			}
+		})
+	}
+}
+
+func TestBitmapIndexQueryErrors(t *testing.T) {
+	bx, _ := newTestBitmapIndex()
+
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "unexpected"},
+		{"online AND", "unexpected"},
+		{"(online OR premium", "expected )"},
+		{"online premium", "unexpected"},
+		{"online # premium", "unexpected character"},
+		{"online AND vip", "unknown predicate \"vip\""},
+		{"NOT", "unexpected"},
+		{")", "unexpected"},
+	}
+	for _, tt := range tests {
+		_, err := bx.Query(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Query(%q) error = %v; want containing %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBitmapIndexEvaluationOrder(t *testing.T) {
+	bx, _ := newTestBitmapIndex()
+	node, err := parseQuery("trial AND online AND banned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	operands := slices.Clone(node.(queryAnd).operands)
+	bx.sortByEstimate(operands, false)
+
+	var names []string
+	for _, op := range operands {
+		names = append(names, op.(queryName).name)
+	}
+	if want := []string{"banned", "online", "trial"}; !slices.Equal(names, want) {
+		t.Errorf("AND evaluation order = %v; want %v", names, want)
+	}
+}
+
+func TestBitmapIndexEstimateIsUpperBound(t *testing.T) {
+	bx, _ := newTestBitmapIndex()
+	for _, expr := range []string{
+		"NOT banned",
+		"NOT (online AND premium)",
+		"NOT (premium OR banned)",
+		"trial AND NOT (online AND premium)",
+		"NOT NOT online",
+	} {
+		node, err := parseQuery(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if est, got := bx.estimate(node), bx.eval(node).Ones(); est < got {
+			t.Errorf("estimate(%q) = %d; below actual %d", expr, est, got)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package succincter
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// BitSlicedIndex indexes an integer column as one Succincter per bit of the value,
+// so range predicates with thresholds chosen at query time are answered without
+// rebuilding. Floats can be indexed by quantizing them in the value function, for
+// example int64(math.Round(v * 10)) for one decimal place.
+//
+// Values are stored relative to the column minimum, so the number of slices is the
+// bit length of max-min. Range, Count and Sum cost O(slices * n/64) word operations.
+type BitSlicedIndex struct {
+	slices []*Succincter // slices[i] holds bit i of value-min
+	min    int64
+	max    int64
+	length int
+}
+
+// NewBitSlicedIndex builds a BitSlicedIndex over value(item) for every item.
+// value is called twice per item.
+func NewBitSlicedIndex[T any](items []T, value func(T) int64) *BitSlicedIndex {
+	bx := &BitSlicedIndex{length: len(items)}
+	if len(items) == 0 {
+		return bx
+	}
+
+	bx.min, bx.max = value(items[0]), value(items[0])
+	for _, item := range items[1:] {
+		v := value(item)
+		bx.min = min(bx.min, v)
+		bx.max = max(bx.max, v)
+	}
+
+	numSlices := bits.Len64(uint64(bx.max - bx.min))
+	words := make([][]uint64, numSlices)
+	for i := range words {
+		words[i] = make([]uint64, (len(items)+63)/64)
+	}
+	for pos, item := range items {
+		for u := uint64(value(item) - bx.min); u != 0; u &= u - 1 {
+			i := internal.TrailingZeros(u)
+			words[i][pos/64] |= 1 << uint(pos%64)
+		}
+	}
+
+	bx.slices = make([]*Succincter, numSlices)
+	for i, w := range words {
+		bx.slices[i] = fromBitVector(w, len(items))
+	}
+	return bx
+}
+
+// Len returns the number of indexed values.
+func (bx *BitSlicedIndex) Len() int {
+	return bx.length
+}
+
+// Min returns the smallest indexed value, or 0 if the index is empty.
+func (bx *BitSlicedIndex) Min() int64 {
+	return bx.min
+}
+
+// Max returns the largest indexed value, or 0 if the index is empty.
+func (bx *BitSlicedIndex) Max() int64 {
+	return bx.max
+}
+
+// Value returns the value at pos. Panics if pos is outside [0, Len()).
+func (bx *BitSlicedIndex) Value(pos int) int64 {
+	if pos < 0 || pos >= bx.length {
+		panic("succincter: BitSlicedIndex position out of range")
+	}
+	u := uint64(0)
+	for i, s := range bx.slices {
+		u |= (s.data[pos/64] >> uint(pos%64) & 1) << uint(i)
+	}
+	return bx.min + int64(u)
+}
+
+// Range returns the positions whose value v satisfies lo <= v < hi.
+func (bx *BitSlicedIndex) Range(lo, hi int64) *Succincter {
+	below := bx.lessThan(hi)
+	notBelow := bx.lessThan(lo)
+	for i := range below {
+		below[i] &^= notBelow[i]
+	}
+	return fromBitVector(below, bx.length)
+}
+
+// Count returns the number of values v with lo <= v < hi.
+func (bx *BitSlicedIndex) Count(lo, hi int64) int {
+	return bx.Range(lo, hi).Ones()
+}
+
+// Select returns the position of the k-th (1-indexed) value v with lo <= v < hi,
+// or -1 if there are fewer than k.
+func (bx *BitSlicedIndex) Select(lo, hi int64, k int) int {
+	return bx.Range(lo, hi).Select(k)
+}
+
+// Sum returns the sum of values at positions set in filter, or of all values if
+// filter is nil. Each slice contributes its popcount under filter times 2^i.
+// Panics if filter's length differs from Len().
+func (bx *BitSlicedIndex) Sum(filter *Succincter) int64 {
+	count := bx.length
+	if filter != nil {
+		if filter.length != bx.length {
+			panic("succincter: BitSlicedIndex filter length does not match")
+		}
+		count = filter.totalOnes
+	}
+	sum := bx.min * int64(count)
+	for i, s := range bx.slices {
+		ones := s.totalOnes
+		if filter != nil {
+			ones = CountAnd(s, filter)
+		}
+		sum += int64(ones) << uint(i)
+	}
+	return sum
+}
+
+// TopK returns the positions of the k largest values among positions set in filter
+// (all positions if filter is nil), ordered by descending value and then ascending
+// position. Returns fewer than k positions if fewer are eligible.
+func (bx *BitSlicedIndex) TopK(k int, filter *Succincter) []int {
+	if k <= 0 || bx.length == 0 {
+		return nil
+	}
+	numWords := (bx.length + 63) / 64
+
+	// greater holds positions known to be in the top k; equal holds positions tied
+	// with the k-th largest on every slice examined so far.
+	greater := make([]uint64, numWords)
+	equal := make([]uint64, numWords)
+	if filter != nil {
+		if filter.length != bx.length {
+			panic("succincter: BitSlicedIndex filter length does not match")
+		}
+		copy(equal, filter.data)
+	} else {
+		fillOnes(equal, bx.length)
+	}
+
+	scratch := make([]uint64, numWords)
+	for i := len(bx.slices) - 1; i >= 0; i-- {
+		slice := bx.slices[i].data
+		count := 0
+		for w := range scratch {
+			scratch[w] = greater[w] | equal[w]&slice[w]
+			count += internal.Popcount(scratch[w])
+		}
+		switch {
+		case count > k:
+			for w := range equal {
+				equal[w] &= slice[w]
+			}
+		case count < k:
+			greater, scratch = scratch, greater
+			for w := range equal {
+				equal[w] &^= slice[w]
+			}
+		default:
+			greater = scratch
+			clear(equal)
+		}
+		if count == k {
+			break
+		}
+	}
+
+	var result []int
+	for w, word := range greater {
+		for ; word != 0; word &= word - 1 {
+			result = append(result, w*64+internal.TrailingZeros(word))
+		}
+	}
+	for w, word := range equal {
+		for ; word != 0 && len(result) < k; word &= word - 1 {
+			result = append(result, w*64+internal.TrailingZeros(word))
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b int) int {
+		va, vb := bx.Value(a), bx.Value(b)
+		switch {
+		case va > vb:
+			return -1
+		case va < vb:
+			return 1
+		}
+		return a - b
+	})
+	return result
+}
+
+// lessThan returns the words of a bitvector marking values below c.
+func (bx *BitSlicedIndex) lessThan(c int64) []uint64 {
+	words := make([]uint64, (bx.length+63)/64)
+	if bx.length == 0 || c <= bx.min {
+		return words
+	}
+	if c > bx.max {
+		fillOnes(words, bx.length)
+		return words
+	}
+
+	// Walk slices from the most significant bit, tracking positions still equal to
+	// the prefix of c and accumulating those already known to be smaller.
+	target := uint64(c - bx.min)
+	equal := make([]uint64, len(words))
+	fillOnes(equal, bx.length)
+	for i := len(bx.slices) - 1; i >= 0; i-- {
+		slice := bx.slices[i].data
+		if target&(uint64(1)<<uint(i)) != 0 {
+			for w := range words {
+				words[w] |= equal[w] &^ slice[w]
+				equal[w] &= slice[w]
+			}
+		} else {
+			for w := range equal {
+				equal[w] &^= slice[w]
+			}
+		}
+	}
+	return words
+}
+
+// fillOnes sets bits [0, length) of words and clears the rest.
+func fillOnes(words []uint64, length int) {
+	for i := range words {
+		words[i] = ^uint64(0)
+	}
+	clearTail(words, length)
+}
@@ -0,0 +1,149 @@
+package succincter
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBitSlicedIndexRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	values := make([]int64, 3000)
+	for i := range values {
+		values[i] = rng.Int63n(1000) - 300
+	}
+	bx := NewBitSlicedIndex(values, func(v int64) int64 { return v })
+
+	for i, v := range values {
+		if got := bx.Value(i); got != v {
+			t.Fatalf("Value(%d) = %d; want %d", i, got, v)
+		}
+	}
+
+	ranges := [][2]int64{{-1000, 2000}, {-300, -299}, {0, 100}, {250, 251}, {699, 700}, {700, 800}, {50, 10}, {-5000, -400}}
+	for _, r := range ranges {
+		var want []int
+		for i, v := range values {
+			if r[0] <= v && v < r[1] {
+				want = append(want, i)
+			}
+		}
+		got := bx.Range(r[0], r[1])
+		if !slices.Equal(slices.Collect(got.Positions()), want) {
+			t.Errorf("Range(%d, %d) differs from brute force", r[0], r[1])
+		}
+		if c := bx.Count(r[0], r[1]); c != len(want) {
+			t.Errorf("Count(%d, %d) = %d; want %d", r[0], r[1], c, len(want))
+		}
+		if len(want) > 2 {
+			if pos := bx.Select(r[0], r[1], 3); pos != want[2] {
+				t.Errorf("Select(%d, %d, 3) = %d; want %d", r[0], r[1], pos, want[2])
+			}
+		}
+	}
+}
+
+func TestBitSlicedIndexSum(t *testing.T) {
+	rng := rand.New(rand.NewSource(25))
+	values := make([]int64, 2000)
+	for i := range values {
+		values[i] = rng.Int63n(5000) - 1000
+	}
+	bx := NewBitSlicedIndex(values, func(v int64) int64 { return v })
+
+	var total, inRange int64
+	for _, v := range values {
+		total += v
+		if 100 <= v && v < 2000 {
+			inRange += v
+		}
+	}
+	if got := bx.Sum(nil); got != total {
+		t.Errorf("Sum(nil) = %d; want %d", got, total)
+	}
+	if got := bx.Sum(bx.Range(100, 2000)); got != inRange {
+		t.Errorf("Sum(Range(100, 2000)) = %d; want %d", got, inRange)
+	}
+}
+
+func TestBitSlicedIndexTopK(t *testing.T) {
+	rng := rand.New(rand.NewSource(26))
+	values := make([]int64, 1500)
+	for i := range values {
+		values[i] = rng.Int63n(200) // many ties
+	}
+	bx := NewBitSlicedIndex(values, func(v int64) int64 { return v })
+
+	bruteTopK := func(k int, keep func(int) bool) []int {
+		var pos []int
+		for i := range values {
+			if keep(i) {
+				pos = append(pos, i)
+			}
+		}
+		slices.SortStableFunc(pos, func(a, b int) int {
+			if values[a] != values[b] {
+				if values[a] > values[b] {
+					return -1
+				}
+				return 1
+			}
+			return a - b
+		})
+		return pos[:min(k, len(pos))]
+	}
+
+	filter := bx.Range(50, 120)
+	for _, k := range []int{1, 5, 17, 100, 2000} {
+		got := bx.TopK(k, nil)
+		want := bruteTopK(k, func(int) bool { return true })
+		// Ties at the cut-off may pick any tied position; compare values.
+		if len(got) != len(want) {
+			t.Fatalf("TopK(%d) returned %d positions; want %d", k, len(got), len(want))
+		}
+		for i := range got {
+			if values[got[i]] != values[want[i]] {
+				t.Fatalf("TopK(%d)[%d] has value %d; want %d", k, i, values[got[i]], values[want[i]])
+			}
+		}
+
+		got = bx.TopK(k, filter)
+		want = bruteTopK(k, func(i int) bool { return 50 <= values[i] && values[i] < 120 })
+		if len(got) != len(want) {
+			t.Fatalf("filtered TopK(%d) returned %d positions; want %d", k, len(got), len(want))
+		}
+		for i := range got {
+			if values[got[i]] != values[want[i]] || values[got[i]] >= 120 {
+				t.Fatalf("filtered TopK(%d)[%d] has value %d; want %d", k, i, values[got[i]], values[want[i]])
+			}
+		}
+	}
+}
+
+func TestBitSlicedIndexQuantizedFloats(t *testing.T) {
+	readings := []float64{19.5, 20.0, 55.25, 80.1, 95.0, -3.2}
+	bx := NewBitSlicedIndex(readings, func(v float64) int64 { return int64(math.Round(v * 10)) })
+
+	if got := bx.Count(200, 801); got != 2 {
+		t.Errorf("Count(20.0 <= v <= 80.0) = %d; want 2", got)
+	}
+	if got := bx.Min(); got != -32 {
+		t.Errorf("Min() = %d; want -32", got)
+	}
+}
+
+func TestBitSlicedIndexConstantAndEmpty(t *testing.T) {
+	constant := NewBitSlicedIndex([]int{7, 7, 7}, func(v int) int64 { return int64(v) })
+	if got := constant.Count(7, 8); got != 3 {
+		t.Errorf("Count(7, 8) on constant column = %d; want 3", got)
+	}
+	if got := constant.Sum(nil); got != 21 {
+		t.Errorf("Sum(nil) on constant column = %d; want 21", got)
+	}
+
+	empty := NewBitSlicedIndex([]int{}, func(v int) int64 { return int64(v) })
+	if empty.Count(math.MinInt64, math.MaxInt64) != 0 || empty.TopK(3, nil) != nil {
+		t.Error("empty index returned matches")
+	}
+}
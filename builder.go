@@ -0,0 +1,81 @@
+package succincter
+
+import "iter"
+
+// Builder constructs a Succincter incrementally, one bit or word at a time.
+// Only the packed bitvector is kept in memory, so inputs can be streamed from an
+// io.Reader or other source that never materializes a []T.
+//
+// The zero value is an empty Builder ready to use. A Builder is not safe for
+// concurrent use.
+type Builder struct {
+	words []uint64
+	n     int
+}
+
+// NewBuilder returns a Builder with room for sizeHint bits before reallocating.
+func NewBuilder(sizeHint int) *Builder {
+	if sizeHint < 0 {
+		sizeHint = 0
+	}
+	return &Builder{words: make([]uint64, 0, (sizeHint+63)/64)}
+}
+
+// Len returns the number of bits appended so far.
+func (b *Builder) Len() int {
+	return b.n
+}
+
+// Append adds a single bit.
+func (b *Builder) Append(bit bool) {
+	off := b.n % 64
+	if off == 0 {
+		b.words = append(b.words, 0)
+	}
+	if bit {
+		b.words[len(b.words)-1] |= 1 << uint(off)
+	}
+	b.n++
+}
+
+// AppendWord adds the low nbits of w, least significant bit first.
+// Panics if nbits is outside [0, 64].
+func (b *Builder) AppendWord(w uint64, nbits int) {
+	if nbits < 0 || nbits > 64 {
+		panic("succincter: AppendWord nbits out of range [0, 64]")
+	}
+	if nbits == 0 {
+		return
+	}
+	if nbits < 64 {
+		w &= (uint64(1) << uint(nbits)) - 1
+	}
+
+	off := b.n % 64
+	if off == 0 {
+		b.words = append(b.words, w)
+	} else {
+		b.words[len(b.words)-1] |= w << uint(off)
+		if off+nbits > 64 {
+			b.words = append(b.words, w>>uint(64-off))
+		}
+	}
+	b.n += nbits
+}
+
+// AppendFunc adds one bit per element of seq, set where predicate returns true.
+// It is a function rather than a method because Go methods cannot take type parameters.
+func AppendFunc[T any](b *Builder, seq iter.Seq[T], predicate func(T) bool) {
+	for v := range seq {
+		b.Append(predicate(v))
+	}
+}
+
+// Build returns a Succincter over the appended bits and resets the Builder.
+// Construction of the rank directory is O(n / 64).
+func (b *Builder) Build() *Succincter {
+	data, n := b.words, b.n
+	b.words = nil
+	b.n = 0
+	return fromBitVector(data, n)
+}
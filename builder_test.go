@@ -0,0 +1,112 @@
+package succincter
+
+import (
+	"bufio"
+	"iter"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuilderAppendMatchesNewSuccincter(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	input := make([]bool, 3000)
+	for i := range input {
+		input[i] = rng.Intn(3) == 0
+	}
+
+	b := NewBuilder(len(input))
+	for _, v := range input {
+		b.Append(v)
+	}
+	if b.Len() != len(input) {
+		t.Fatalf("Len() = %d; want %d", b.Len(), len(input))
+	}
+
+	got := b.Build()
+	want := NewSuccincter(input, func(v bool) bool { return v })
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal("Builder.Append result differs from NewSuccincter")
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len() after Build = %d; want 0", b.Len())
+	}
+}
+
+func TestBuilderAppendWord(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	var input []bool
+	var b Builder
+
+	for i := 0; i < 500; i++ {
+		w := rng.Uint64()
+		nbits := rng.Intn(65)
+		b.AppendWord(w, nbits)
+		for j := 0; j < nbits; j++ {
+			input = append(input, w&(uint64(1)<<uint(j)) != 0)
+		}
+		if i%7 == 0 {
+			bit := rng.Intn(2) == 0
+			b.Append(bit)
+			input = append(input, bit)
+		}
+	}
+
+	got := b.Build()
+	want := NewSuccincter(input, func(v bool) bool { return v })
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal("Builder.AppendWord result differs from NewSuccincter")
+	}
+}
+
+func TestBuilderAppendWordPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("AppendWord(_, 65) did not panic")
+		}
+	}()
+	var b Builder
+	b.AppendWord(0, 65)
+}
+
+func TestAppendFuncFromReader(t *testing.T) {
+	logs := "INFO start\nERROR disk\nINFO ok\nWARN slow\nERROR net\n"
+
+	lines := func(r *strings.Reader) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			sc := bufio.NewScanner(r)
+			for sc.Scan() {
+				if !yield(sc.Text()) {
+					return
+				}
+			}
+		}
+	}
+
+	var b Builder
+	AppendFunc(&b, lines(strings.NewReader(logs)), func(line string) bool {
+		return strings.HasPrefix(line, "ERROR")
+	})
+	s := b.Build()
+
+	if got := s.Rank(5); got != 2 {
+		t.Errorf("Rank(5) = %d; want 2", got)
+	}
+	if got := s.Select(1); got != 1 {
+		t.Errorf("Select(1) = %d; want 1", got)
+	}
+	if got := s.Select(2); got != 4 {
+		t.Errorf("Select(2) = %d; want 4", got)
+	}
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	s := NewBuilder(0).Build()
+	if got := s.Rank(10); got != 0 {
+		t.Errorf("Rank(10) on empty = %d; want 0", got)
+	}
+	if got := s.Select(1); got != -1 {
+		t.Errorf("Select(1) on empty = %d; want -1", got)
+	}
+}
@@ -0,0 +1,147 @@
+package succincter
+
+import "sort"
+
+// CategoryIndex indexes a low-cardinality column whose categories have a natural
+// order, such as log levels DEBUG < INFO < WARN < ERROR.
+//
+// Each category c has an equality-encoded bitvector (key == c) and a range-encoded
+// one (key <= c). Any contiguous range of categories is the difference of two
+// range-encoded bitvectors, so Rank over "level >= WARN" costs two Rank calls no
+// matter how many categories the range spans.
+type CategoryIndex[K comparable] struct {
+	categories []K
+	order      map[K]int
+	equal      []*Succincter // equal[i]: key == categories[i]
+	atMost     []*Succincter // atMost[i]: key <= categories[i]; the last category is implicit
+	length     int
+}
+
+// NewCategoryIndex builds a CategoryIndex over key(item) for every item. categories
+// lists every possible key in ascending order. Panics if a key is not in categories
+// or categories contains duplicates.
+func NewCategoryIndex[T any, K comparable](items []T, categories []K, key func(T) K) *CategoryIndex[K] {
+	cx := &CategoryIndex[K]{
+		categories: categories,
+		order:      make(map[K]int, len(categories)),
+		length:     len(items),
+	}
+	for i, c := range categories {
+		if _, dup := cx.order[c]; dup {
+			panic("succincter: CategoryIndex has duplicate category")
+		}
+		cx.order[c] = i
+	}
+
+	numWords := (len(items) + 63) / 64
+	equal := make([][]uint64, len(categories))
+	for i := range equal {
+		equal[i] = make([]uint64, numWords)
+	}
+	for pos, item := range items {
+		i, ok := cx.order[key(item)]
+		if !ok {
+			panic("succincter: CategoryIndex key is not a listed category")
+		}
+		equal[i][pos/64] |= 1 << uint(pos%64)
+	}
+
+	cx.equal = make([]*Succincter, len(categories))
+	cx.atMost = make([]*Succincter, max(len(categories)-1, 0))
+	running := make([]uint64, numWords)
+	for i, words := range equal {
+		cx.equal[i] = fromBitVector(words, len(items))
+		if i == len(categories)-1 {
+			break
+		}
+		for w := range running {
+			running[w] |= words[w]
+		}
+		cx.atMost[i] = fromBitVector(append([]uint64(nil), running...), len(items))
+	}
+	return cx
+}
+
+// Len returns the number of indexed items.
+func (cx *CategoryIndex[K]) Len() int {
+	return cx.length
+}
+
+// Categories returns the categories in ascending order.
+func (cx *CategoryIndex[K]) Categories() []K {
+	return cx.categories
+}
+
+// Equal returns the bitvector of positions whose key is c.
+func (cx *CategoryIndex[K]) Equal(c K) *Succincter {
+	return cx.equal[cx.indexOf(c)]
+}
+
+// Range returns a RankSelector over positions whose key is between lo and hi
+// inclusive in category order. It is empty if lo comes after hi.
+func (cx *CategoryIndex[K]) Range(lo, hi K) RankSelector {
+	return &categoryRange[K]{cx: cx, lo: cx.indexOf(lo), hi: cx.indexOf(hi)}
+}
+
+// AtLeast returns a RankSelector over positions whose key is c or later in order.
+func (cx *CategoryIndex[K]) AtLeast(c K) RankSelector {
+	return cx.Range(c, cx.categories[len(cx.categories)-1])
+}
+
+// AtMost returns a RankSelector over positions whose key is c or earlier in order.
+func (cx *CategoryIndex[K]) AtMost(c K) RankSelector {
+	return cx.Range(cx.categories[0], c)
+}
+
+// Count returns the number of items whose key is between lo and hi inclusive.
+func (cx *CategoryIndex[K]) Count(lo, hi K) int {
+	return cx.Range(lo, hi).Rank(cx.length)
+}
+
+func (cx *CategoryIndex[K]) indexOf(c K) int {
+	i, ok := cx.order[c]
+	if !ok {
+		panic("succincter: CategoryIndex category is not listed")
+	}
+	return i
+}
+
+// rankAtMost returns the number of positions before pos with category index <= i.
+func (cx *CategoryIndex[K]) rankAtMost(i, pos int) int {
+	switch {
+	case i < 0:
+		return 0
+	case i >= len(cx.atMost):
+		return max(0, min(pos, cx.length))
+	}
+	return cx.atMost[i].Rank(pos)
+}
+
+type categoryRange[K comparable] struct {
+	cx     *CategoryIndex[K]
+	lo, hi int
+}
+
+// Rank returns the count of matching positions before pos.
+func (r *categoryRange[K]) Rank(pos int) int {
+	if r.lo > r.hi {
+		return 0
+	}
+	if r.lo == r.hi {
+		return r.cx.equal[r.lo].Rank(pos)
+	}
+	return r.cx.rankAtMost(r.hi, pos) - r.cx.rankAtMost(r.lo-1, pos)
+}
+
+// Select returns the position of the rank-th matching position (1-indexed), or -1.
+// Single categories use their equality bitvector; wider ranges binary search on Rank,
+// costing O(log n) pairs of Rank calls.
+func (r *categoryRange[K]) Select(rank int) int {
+	if r.lo > r.hi || rank <= 0 || rank > r.Rank(r.cx.length) {
+		return -1
+	}
+	if r.lo == r.hi {
+		return r.cx.equal[r.lo].Select(rank)
+	}
+	return sort.Search(r.cx.length, func(pos int) bool { return r.Rank(pos+1) >= rank })
+}
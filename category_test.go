@@ -0,0 +1,76 @@
+package succincter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type testLogEntry struct {
+	Level string
+}
+
+var testLevels = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+func TestCategoryIndexRanges(t *testing.T) {
+	rng := rand.New(rand.NewSource(27))
+	logs := make([]testLogEntry, 3000)
+	for i := range logs {
+		logs[i] = testLogEntry{Level: testLevels[rng.Intn(len(testLevels))]}
+	}
+	cx := NewCategoryIndex(logs, testLevels, func(e testLogEntry) string { return e.Level })
+
+	level := func(name string) int {
+		for i, l := range testLevels {
+			if l == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for lo := range testLevels {
+		for hi := range testLevels {
+			var model []bool
+			for _, e := range logs {
+				model = append(model, lo <= level(e.Level) && level(e.Level) <= hi)
+			}
+			r := cx.Range(testLevels[lo], testLevels[hi])
+			checkRankSelect(t, r, model)
+		}
+	}
+
+	warnOrAbove := 0
+	for _, e := range logs {
+		if level(e.Level) >= level("WARN") {
+			warnOrAbove++
+		}
+	}
+	if got := cx.AtLeast("WARN").Rank(len(logs)); got != warnOrAbove {
+		t.Errorf("AtLeast(WARN).Rank(n) = %d; want %d", got, warnOrAbove)
+	}
+	if got := cx.Count("WARN", "ERROR"); got != warnOrAbove {
+		t.Errorf("Count(WARN, ERROR) = %d; want %d", got, warnOrAbove)
+	}
+	if got := cx.AtMost("INFO").Rank(len(logs)); got != len(logs)-warnOrAbove {
+		t.Errorf("AtMost(INFO).Rank(n) = %d; want %d", got, len(logs)-warnOrAbove)
+	}
+	if got := cx.Equal("ERROR").Ones() + cx.Equal("WARN").Ones(); got != warnOrAbove {
+		t.Errorf("Equal(ERROR)+Equal(WARN) = %d; want %d", got, warnOrAbove)
+	}
+}
+
+func TestCategoryIndexUnknownKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCategoryIndex with unlisted key did not panic")
+		}
+	}()
+	NewCategoryIndex([]string{"INFO", "FATAL"}, testLevels, func(s string) string { return s })
+}
+
+func TestCategoryIndexSingleCategory(t *testing.T) {
+	cx := NewCategoryIndex([]int{1, 1, 1}, []int{1}, func(v int) int { return v })
+	if got := cx.AtLeast(1).Select(3); got != 2 {
+		t.Errorf("Select(3) = %d; want 2", got)
+	}
+}
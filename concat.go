@@ -0,0 +1,82 @@
+package succincter
+
+import "github.com/shaia/succincter/internal"
+
+// Concat returns a new Succincter over the bits of a followed by the bits of b,
+// without re-evaluating any predicate.
+//
+// a's words and directory entries are copied as-is. b's words are shifted across the
+// word boundary when a.Len() is not a multiple of 64; when it is, b's block ranks are
+// reused by adding a's total instead of being re-counted.
+func Concat(a, b *Succincter) *Succincter {
+	length := a.length + b.length
+	data := make([]uint64, (length+63)/64)
+	copy(data, a.data)
+
+	off := uint(a.length % 64)
+	start := a.length / 64
+	for i, w := range b.data {
+		if off == 0 {
+			data[start+i] = w
+			continue
+		}
+		data[start+i] |= w << off
+		if start+i+1 < len(data) {
+			data[start+i+1] = w >> (64 - off)
+		}
+	}
+
+	blockRanks := make([]uint64, len(data))
+	copy(blockRanks, a.blockRanks)
+	if off == 0 {
+		for i, r := range b.blockRanks {
+			blockRanks[start+i] = r + uint64(a.totalOnes)
+		}
+	} else {
+		// Blocks before a's last (partial) word are unchanged.
+		rank := uint64(0)
+		if start > 0 {
+			rank = blockRanks[start-1] + uint64(internal.Popcount(data[start-1]))
+		}
+		for i := start; i < len(data); i++ {
+			blockRanks[i] = rank
+			rank += uint64(internal.Popcount(data[i]))
+		}
+	}
+
+	return newSuccincter(data, blockRanks, superBlocksFrom(blockRanks), a.totalOnes+b.totalOnes, length)
+}
+
+// Slice returns a new Succincter over positions [lo, hi) of s, so that position lo
+// of s becomes position 0 of the result. Panics if the range is invalid.
+func (s *Succincter) Slice(lo, hi int) *Succincter {
+	if lo < 0 || hi < lo || hi > s.length {
+		panic("succincter: Slice bounds out of range")
+	}
+	return fromBitVector(extractBits(s.data, lo, hi), hi-lo)
+}
+
+// superBlocksFrom samples a complete blockRanks array every blocksPerSuperBlock entries.
+func superBlocksFrom(blockRanks []uint64) []uint64 {
+	superBlocks := make([]uint64, 0, (len(blockRanks)+blocksPerSuperBlock-1)/blocksPerSuperBlock)
+	for i := 0; i < len(blockRanks); i += blocksPerSuperBlock {
+		superBlocks = append(superBlocks, blockRanks[i])
+	}
+	return superBlocks
+}
+
+// extractBits copies bits [lo, hi) of data into a new, zero-padded word slice.
+func extractBits(data []uint64, lo, hi int) []uint64 {
+	out := make([]uint64, (hi-lo+63)/64)
+	off := uint(lo % 64)
+	first := lo / 64
+	for i := range out {
+		w := data[first+i] >> off
+		if off != 0 && first+i+1 < len(data) {
+			w |= data[first+i+1] << (64 - off)
+		}
+		out[i] = w
+	}
+	clearTail(out, hi-lo)
+	return out
+}
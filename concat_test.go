@@ -0,0 +1,56 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	rng := rand.New(rand.NewSource(20))
+	sizes := []int{0, 1, 63, 64, 65, 1024, 1030, 2048, 3001}
+
+	for _, na := range sizes {
+		for _, nb := range sizes {
+			x, y := randomBools(rng, na), randomBools(rng, nb)
+			got := Concat(FromBools(x), FromBools(y))
+			want := FromBools(append(append([]bool{}, x...), y...))
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("Concat(len %d, len %d) differs from FromBools of concatenation", na, nb)
+			}
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	input := randomBools(rng, 3000)
+	s := FromBools(input)
+
+	ranges := [][2]int{{0, 0}, {0, 3000}, {1, 2}, {63, 129}, {64, 128}, {100, 2999}, {1024, 2048}, {2999, 3000}}
+	for _, r := range ranges {
+		got := s.Slice(r[0], r[1])
+		want := FromBools(input[r[0]:r[1]])
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Slice(%d, %d) differs from FromBools of sub-slice", r[0], r[1])
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Slice past Len did not panic")
+		}
+	}()
+	s.Slice(10, 3001)
+}
+
+func TestSliceConcatRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	input := randomBools(rng, 5000)
+	s := FromBools(input)
+
+	joined := Concat(Concat(s.Slice(0, 1234), s.Slice(1234, 4000)), s.Slice(4000, 5000))
+	if !reflect.DeepEqual(joined, s) {
+		t.Error("concatenating slices does not reproduce the original")
+	}
+}
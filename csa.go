@@ -0,0 +1,298 @@
+package succincter
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// csaSampleRate is the text distance between stored SA and ISA samples. SA and ISA
+// lookups take at most this many Ψ steps.
+const csaSampleRate = 32
+
+// CSA is a compressed suffix array over a sequence of symbols from a small ordered
+// alphabet, such as the nucleotides of a DNA sequence.
+//
+// It stores the Ψ function (Ψ[i] is the suffix-array index of the suffix one symbol
+// shorter than suffix i) as a single Elias-Fano sequence: within the block of suffixes
+// starting with the same symbol Ψ is increasing, so offsetting each block by its
+// symbol makes the whole sequence monotone. SA and ISA values are sampled every
+// csaSampleRate text positions and recovered by walking Ψ; LCP values come from the
+// permuted LCP array, also stored as Elias-Fano, in about 2n bits.
+//
+// Positions and suffix-array indices are 0-based over the original text; the
+// internal end-of-text sentinel is not visible. Construction uses O(n) words of
+// temporary memory.
+type CSA[T comparable] struct {
+	alphabet []T
+	codes    map[T]int
+	n        int   // text length without the sentinel
+	starts   []int // starts[c]: first internal SA index of suffixes starting with code c
+
+	psi       *EFSequence // Ψ[i] + code(i)*(n+1)
+	sampled   *Succincter
+	saSamples *internal.PackedInts
+	isaSample *internal.PackedInts
+	plcp      *EFSequence // PLCP[j] + j
+
+	repeatPos, repeatLen int
+}
+
+// NewCSA builds a compressed suffix array over text. alphabet lists every symbol that
+// may occur, in ascending order. Panics if text contains a symbol not in alphabet or
+// alphabet contains duplicates.
+func NewCSA[T comparable](text []T, alphabet []T) *CSA[T] {
+	cx := &CSA[T]{alphabet: alphabet, codes: make(map[T]int, len(alphabet)), n: len(text)}
+	for i, sym := range alphabet {
+		if _, dup := cx.codes[sym]; dup {
+			panic("succincter: CSA alphabet has duplicate symbol")
+		}
+		cx.codes[sym] = i + 1 // code 0 is the sentinel
+	}
+
+	// Internal text: symbol codes followed by the sentinel.
+	n := len(text) + 1
+	codes := make([]int, n)
+	for i, sym := range text {
+		c, ok := cx.codes[sym]
+		if !ok {
+			panic("succincter: CSA text symbol is not in alphabet")
+		}
+		codes[i] = c
+	}
+
+	sa := buildSuffixArray(codes)
+	isa := make([]int, n)
+	for i, p := range sa {
+		isa[p] = i
+	}
+
+	cx.starts = make([]int, len(alphabet)+2)
+	for _, c := range codes {
+		cx.starts[c+1]++
+	}
+	for c := 1; c < len(cx.starts); c++ {
+		cx.starts[c] += cx.starts[c-1]
+	}
+
+	psiBuilder := newEliasFanoBuilder(n, uint64(len(alphabet)+1)*uint64(n)-1)
+	for _, p := range sa {
+		psiBuilder.push(uint64(codes[p])*uint64(n) + uint64(isa[(p+1)%n]))
+	}
+	cx.psi = psiBuilder.build()
+
+	width := bits.Len(uint(n))
+	sampled := make([]bool, n)
+	var saValues []int
+	for i, p := range sa {
+		if p%csaSampleRate == 0 || p == n-1 {
+			sampled[i] = true
+			saValues = append(saValues, p)
+		}
+	}
+	cx.sampled = FromBools(sampled)
+	cx.saSamples = internal.NewPackedInts(len(saValues), width)
+	for k, p := range saValues {
+		cx.saSamples.Set(k, uint64(p))
+	}
+	cx.isaSample = internal.NewPackedInts((n+csaSampleRate-1)/csaSampleRate, width)
+	for j := 0; j < n; j += csaSampleRate {
+		cx.isaSample.Set(j/csaSampleRate, uint64(isa[j]))
+	}
+
+	// Kasai's algorithm: PLCP[j] >= PLCP[j-1]-1, so PLCP[j]+j never decreases.
+	plcpBuilder := newEliasFanoBuilder(n, uint64(2*n))
+	h := 0
+	cx.repeatPos = -1
+	for j := 0; j < n; j++ {
+		i := isa[j]
+		if i == 0 {
+			h = 0
+		} else {
+			k := sa[i-1]
+			for j+h < n && k+h < n && codes[j+h] == codes[k+h] && codes[j+h] != 0 {
+				h++
+			}
+		}
+		plcpBuilder.push(uint64(h + j))
+		if h > cx.repeatLen {
+			cx.repeatPos, cx.repeatLen = j, h
+		}
+		if h > 0 {
+			h--
+		}
+	}
+	cx.plcp = plcpBuilder.build()
+	return cx
+}
+
+// Len returns the length of the text.
+func (cx *CSA[T]) Len() int {
+	return cx.n
+}
+
+// SA returns the text position of the i-th smallest suffix. Panics if i is outside
+// [0, Len()).
+func (cx *CSA[T]) SA(i int) int {
+	if i < 0 || i >= cx.n {
+		panic("succincter: CSA index out of range")
+	}
+	return cx.sa(i + 1)
+}
+
+// ISA returns the rank among all suffixes of the suffix starting at text position j.
+// Panics if j is outside [0, Len()).
+func (cx *CSA[T]) ISA(j int) int {
+	if j < 0 || j >= cx.n {
+		panic("succincter: CSA position out of range")
+	}
+	i := int(cx.isaSample.Get(j / csaSampleRate))
+	for k := 0; k < j%csaSampleRate; k++ {
+		i = cx.psiAt(i)
+	}
+	return i - 1
+}
+
+// LCP returns the length of the longest common prefix of suffixes SA(i-1) and SA(i),
+// and 0 for i == 0. Panics if i is outside [0, Len()).
+func (cx *CSA[T]) LCP(i int) int {
+	if i < 0 || i >= cx.n {
+		panic("succincter: CSA index out of range")
+	}
+	if i == 0 {
+		return 0
+	}
+	j := cx.sa(i + 1)
+	return int(cx.plcp.Access(j)) - j
+}
+
+// LongestRepeat returns a text position and length of the longest substring that
+// occurs at least twice, or (-1, 0) if no symbol repeats.
+func (cx *CSA[T]) LongestRepeat() (pos, length int) {
+	return cx.repeatPos, cx.repeatLen
+}
+
+// Range returns the suffix-array interval [lo, hi) of suffixes starting with
+// pattern, so hi-lo is its number of occurrences and SA(lo..hi-1) their positions.
+// Comparisons extract suffix symbols by walking Ψ, costing O(len(pattern) log n)
+// per probe.
+func (cx *CSA[T]) Range(pattern []T) (lo, hi int) {
+	codes := make([]int, len(pattern))
+	for k, sym := range pattern {
+		c, ok := cx.codes[sym]
+		if !ok {
+			return 0, 0
+		}
+		codes[k] = c
+	}
+	// Internal indices 1..n hold the real suffixes; 0 is the sentinel.
+	lo = sort.Search(cx.n, func(i int) bool { return cx.comparePrefix(i+1, codes) >= 0 })
+	hi = sort.Search(cx.n, func(i int) bool { return cx.comparePrefix(i+1, codes) > 0 })
+	return lo, hi
+}
+
+// Locate returns the text positions of every occurrence of pattern, in suffix order.
+func (cx *CSA[T]) Locate(pattern []T) []int {
+	lo, hi := cx.Range(pattern)
+	positions := make([]int, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		positions = append(positions, cx.SA(i))
+	}
+	return positions
+}
+
+// Extract returns length symbols of the text starting at position j, clipped to the
+// end of the text.
+func (cx *CSA[T]) Extract(j, length int) []T {
+	if j < 0 || j > cx.n {
+		panic("succincter: CSA position out of range")
+	}
+	length = min(length, cx.n-j)
+	out := make([]T, 0, max(length, 0))
+	if length <= 0 {
+		return out
+	}
+	i := cx.ISA(j) + 1
+	for k := 0; k < length; k++ {
+		out = append(out, cx.alphabet[cx.codeAt(i)-1])
+		i = cx.psiAt(i)
+	}
+	return out
+}
+
+// comparePrefix compares the first len(codes) symbols of internal suffix i with codes:
+// negative if the suffix sorts before the pattern, 0 if it starts with it.
+func (cx *CSA[T]) comparePrefix(i int, codes []int) int {
+	for _, want := range codes {
+		got := cx.codeAt(i)
+		if got != want {
+			return got - want
+		}
+		i = cx.psiAt(i)
+	}
+	return 0
+}
+
+// sa returns the text position of internal suffix i by walking Ψ to a sample.
+func (cx *CSA[T]) sa(i int) int {
+	steps := 0
+	for cx.sampled.Rank(i+1)-cx.sampled.Rank(i) == 0 {
+		i = cx.psiAt(i)
+		steps++
+	}
+	return int(cx.saSamples.Get(cx.sampled.Rank(i))) - steps
+}
+
+// codeAt returns the code of the first symbol of internal suffix i.
+func (cx *CSA[T]) codeAt(i int) int {
+	return sort.Search(len(cx.starts), func(c int) bool { return cx.starts[c] > i }) - 1
+}
+
+func (cx *CSA[T]) psiAt(i int) int {
+	return int(cx.psi.Access(i) - uint64(cx.codeAt(i))*uint64(cx.n+1))
+}
+
+// buildSuffixArray returns the suffix array of codes, whose last element must be a
+// unique smallest sentinel, by prefix doubling in O(n log^2 n) time.
+func buildSuffixArray(codes []int) []int {
+	n := len(codes)
+	sa := make([]int, n)
+	rank := make([]int, n)
+	tmp := make([]int, n)
+	for i := range sa {
+		sa[i] = i
+		rank[i] = codes[i]
+	}
+
+	for k := 1; ; k *= 2 {
+		key := func(i int) (int, int) {
+			second := -1
+			if i+k < n {
+				second = rank[i+k]
+			}
+			return rank[i], second
+		}
+		sort.Slice(sa, func(a, b int) bool {
+			ra, sa2 := key(sa[a])
+			rb, sb2 := key(sa[b])
+			if ra != rb {
+				return ra < rb
+			}
+			return sa2 < sb2
+		})
+		tmp[sa[0]] = 0
+		for i := 1; i < n; i++ {
+			pr, ps := key(sa[i-1])
+			cr, cs := key(sa[i])
+			tmp[sa[i]] = tmp[sa[i-1]]
+			if pr != cr || ps != cs {
+				tmp[sa[i]]++
+			}
+		}
+		copy(rank, tmp)
+		if rank[sa[n-1]] == n-1 {
+			return sa
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package succincter
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func naiveSuffixArray(text string) []int {
+	sa := make([]int, len(text))
+	for i := range sa {
+		sa[i] = i
+	}
+	sort.Slice(sa, func(a, b int) bool { return text[sa[a]:] < text[sa[b]:] })
+	return sa
+}
+
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func TestCSA(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	alphabet := []byte("ACGT")
+	texts := []string{"", "A", "AAAA", "ACGTACGTACGT", "GATTACA", "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT"}
+	for _, n := range []int{100, 1000, 3000} {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.Intn(4)]
+		}
+		texts = append(texts, string(b))
+	}
+
+	for _, text := range texts {
+		cx := NewCSA([]byte(text), alphabet)
+		sa := naiveSuffixArray(text)
+		if cx.Len() != len(text) {
+			t.Fatalf("Len() = %d, want %d", cx.Len(), len(text))
+		}
+		wantLen := 0
+		for i, p := range sa {
+			if got := cx.SA(i); got != p {
+				t.Fatalf("len %d: SA(%d) = %d, want %d", len(text), i, got, p)
+			}
+			if got := cx.ISA(p); got != i {
+				t.Fatalf("len %d: ISA(%d) = %d, want %d", len(text), p, got, i)
+			}
+			lcp := 0
+			if i > 0 {
+				lcp = commonPrefix(text[sa[i-1]:], text[p:])
+			}
+			if got := cx.LCP(i); got != lcp {
+				t.Fatalf("len %d: LCP(%d) = %d, want %d", len(text), i, got, lcp)
+			}
+			if lcp > wantLen {
+				wantLen = lcp
+			}
+		}
+		pos, length := cx.LongestRepeat()
+		if length != wantLen {
+			t.Fatalf("len %d: LongestRepeat length = %d, want %d", len(text), length, wantLen)
+		}
+		if length == 0 && pos != -1 {
+			t.Fatalf("len %d: LongestRepeat pos = %d with no repeat, want -1", len(text), pos)
+		}
+		if length > 0 && !occursTwice(text, text[pos:pos+length]) {
+			t.Fatalf("len %d: LongestRepeat substring %q does not repeat", len(text), text[pos:pos+length])
+		}
+		if len(text) > 10 {
+			if got := string(cx.Extract(3, 7)); got != text[3:10] {
+				t.Fatalf("Extract(3, 7) = %q, want %q", got, text[3:10])
+			}
+		}
+	}
+}
+
+// occursTwice reports whether sub occurs at two positions, counting overlaps.
+func occursTwice(text, sub string) bool {
+	first := strings.Index(text, sub)
+	return first >= 0 && strings.Contains(text[first+1:], sub)
+}
+
+func TestCSALocate(t *testing.T) {
+	text := "ACGTTACGATACGTACG"
+	cx := NewCSA([]byte(text), []byte("ACGT"))
+	for _, pattern := range []string{"ACG", "TACG", "G", "ACGTACG", "TTT", "ACGTTACGATACGTACG"} {
+		var want []int
+		for i := 0; i+len(pattern) <= len(text); i++ {
+			if text[i:i+len(pattern)] == pattern {
+				want = append(want, i)
+			}
+		}
+		got := cx.Locate([]byte(pattern))
+		slices.Sort(got)
+		if len(got) != len(want) || (len(want) > 0 && !slices.Equal(got, want)) {
+			t.Errorf("Locate(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+	if lo, hi := cx.Range([]byte("ACN")); lo != hi {
+		t.Errorf("Range with unknown symbol = [%d, %d), want empty", lo, hi)
+	}
+}
+
+func TestCSAPanics(t *testing.T) {
+	cx := NewCSA([]byte("ACGT"), []byte("ACGT"))
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"unknown symbol", func() { NewCSA([]byte("ACGN"), []byte("ACGT")) }},
+		{"duplicate alphabet", func() { NewCSA([]byte("AC"), []byte("ACA")) }},
+		{"SA at end", func() { cx.SA(4) }},
+		{"ISA negative", func() { cx.ISA(-1) }},
+		{"LCP at end", func() { cx.LCP(4) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func BenchmarkCSASA(b *testing.B) {
+	rng := rand.New(rand.NewSource(43))
+	text := make([]byte, 1_000_000)
+	for i := range text {
+		text[i] = "ACGT"[rng.Intn(4)]
+	}
+	cx := NewCSA(text, []byte("ACGT"))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cx.SA(rng.Intn(len(text)))
+	}
+}
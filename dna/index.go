@@ -0,0 +1,189 @@
+package dna
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/shaia/succincter"
+)
+
+// Index holds one bitvector per nucleotide over the concatenated sequences of every
+// record in a file, plus one for ambiguous bases (N and the other IUPAC codes), so
+// base counts and positions in any region are rank and select queries.
+//
+// Record boundaries are kept as a unary-coded bitvector (one 0 per base, a 1 after
+// each record), which maps positions to records with Select0 and allows empty
+// records. Positions are 0-based over the concatenation.
+type Index struct {
+	bases     [4]*succincter.Succincter // A, C, G, T
+	ambiguous *succincter.Succincter
+	ends      *succincter.Succincter
+	names     []string
+	length    int
+}
+
+// column indexes into Index.bases; ambiguousColumn follows the four bases.
+const ambiguousColumn = 4
+
+// Build reads a FASTA or FASTQ file and indexes its sequences. Bases are
+// case-insensitive and U is read as T. Other letters and the gap characters '-', '.'
+// and '*' are ambiguous; spaces and tabs are ignored. Any other byte is an error.
+func Build(r io.Reader) (*Index, error) {
+	var cols columns
+	for k := range cols.builders {
+		cols.builders[k] = succincter.NewBuilder(0)
+	}
+	ends := succincter.NewBuilder(0)
+	var names []string
+	var invalid error
+
+	err := Scan(r, func(name string) {
+		if len(names) > 0 {
+			ends.Append(true)
+		}
+		names = append(names, name)
+	}, func(chunk []byte) {
+		for _, b := range chunk {
+			col := baseColumn(b)
+			if col < 0 {
+				if b != ' ' && b != '\t' && b != '\r' && invalid == nil {
+					invalid = fmt.Errorf("dna: invalid character %q in record %q", b, names[len(names)-1])
+				}
+				continue
+			}
+			cols.add(col)
+			ends.Append(false)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	if len(names) > 0 {
+		ends.Append(true)
+	}
+	cols.flush()
+
+	x := &Index{
+		ambiguous: cols.builders[ambiguousColumn].Build(),
+		ends:      ends.Build(),
+		names:     names,
+	}
+	for k := range x.bases {
+		x.bases[k] = cols.builders[k].Build()
+	}
+	x.length = x.ambiguous.Len()
+	return x, nil
+}
+
+// Len returns the total number of bases across all records.
+func (x *Index) Len() int {
+	return x.length
+}
+
+// NumRecords returns the number of records.
+func (x *Index) NumRecords() int {
+	return len(x.names)
+}
+
+// Name returns the header of record i, without the '>' or '@' marker.
+func (x *Index) Name(i int) string {
+	return x.names[i]
+}
+
+// Span returns the positions [start, end) of record i. Panics if i is out of range.
+func (x *Index) Span(i int) (start, end int) {
+	if i < 0 || i >= len(x.names) {
+		panic("dna: record index out of range")
+	}
+	// The i-th 1-bit (1-indexed) closes record i-1; zeros before it are bases.
+	if i > 0 {
+		start = x.ends.Select(i) - i + 1
+	}
+	return start, x.ends.Select(i+1) - i
+}
+
+// Record returns the record containing position pos, or -1 if pos is out of range.
+func (x *Index) Record(pos int) int {
+	if pos < 0 || pos >= x.length {
+		return -1
+	}
+	// Every 1-bit before the base's 0-bit closes an earlier record.
+	return x.ends.Select0(pos+1) - pos
+}
+
+// Bitvector returns the bitvector of positions holding base, one of A, C, G, T
+// (either case), or N for ambiguous bases. Panics for any other byte.
+func (x *Index) Bitvector(base byte) *succincter.Succincter {
+	switch col := baseColumn(base); {
+	case col >= 0 && col < ambiguousColumn:
+		return x.bases[col]
+	case base == 'N' || base == 'n':
+		return x.ambiguous
+	}
+	panic(fmt.Sprintf("dna: no bitvector for base %q", base))
+}
+
+// Count returns the number of positions in [lo, hi) holding base, as in Bitvector.
+func (x *Index) Count(base byte, lo, hi int) int {
+	bv := x.Bitvector(base)
+	return bv.Rank(hi) - bv.Rank(lo)
+}
+
+// At returns the base at pos as A, C, G, T or N. Panics if pos is out of range.
+func (x *Index) At(pos int) byte {
+	if pos < 0 || pos >= x.length {
+		panic("dna: position out of range")
+	}
+	for k, bv := range x.bases {
+		if bv.Rank(pos+1) != bv.Rank(pos) {
+			return "ACGT"[k]
+		}
+	}
+	return 'N'
+}
+
+// baseColumn maps a sequence byte to its column, or -1 if it is not a base.
+func baseColumn(b byte) int {
+	switch b {
+	case 'A', 'a':
+		return 0
+	case 'C', 'c':
+		return 1
+	case 'G', 'g':
+		return 2
+	case 'T', 't', 'U', 'u':
+		return 3
+	case '-', '.', '*':
+		return ambiguousColumn
+	}
+	if b|0x20 >= 'a' && b|0x20 <= 'z' {
+		return ambiguousColumn
+	}
+	return -1
+}
+
+// columns accumulates one word per column so each base costs a bit OR, not a
+// Builder call.
+type columns struct {
+	builders [5]*succincter.Builder
+	words    [5]uint64
+	n        int
+}
+
+func (c *columns) add(col int) {
+	c.words[col] |= 1 << uint(c.n)
+	if c.n++; c.n == 64 {
+		c.flush()
+	}
+}
+
+func (c *columns) flush() {
+	for k, b := range c.builders {
+		b.AppendWord(c.words[k], c.n)
+		c.words[k] = 0
+	}
+	c.n = 0
+}
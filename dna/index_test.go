@@ -0,0 +1,97 @@
+package dna
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	rng := rand.New(rand.NewSource(45))
+	var file strings.Builder
+	var seqs []string
+	for i := 0; i < 50; i++ {
+		n := rng.Intn(300)
+		if i%10 == 3 {
+			n = 0
+		}
+		seq := make([]byte, n)
+		for j := range seq {
+			seq[j] = "ACGTacgtNRY-"[rng.Intn(12)]
+		}
+		seqs = append(seqs, string(seq))
+		file.WriteString(">rec" + string(rune('A'+i%26)) + "\n")
+		for len(seq) > 60 {
+			file.Write(seq[:60])
+			file.WriteString("\n")
+			seq = seq[60:]
+		}
+		file.Write(seq)
+		file.WriteString("\n")
+	}
+
+	x, err := Build(strings.NewReader(file.String()))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	concat := strings.Join(seqs, "")
+	if x.Len() != len(concat) || x.NumRecords() != len(seqs) {
+		t.Fatalf("Len, NumRecords = %d, %d; want %d, %d", x.Len(), x.NumRecords(), len(concat), len(seqs))
+	}
+
+	pos := 0
+	for i, seq := range seqs {
+		if start, end := x.Span(i); start != pos || end != pos+len(seq) {
+			t.Fatalf("Span(%d) = [%d, %d); want [%d, %d)", i, start, end, pos, pos+len(seq))
+		}
+		for j := range seq {
+			if got := x.Record(pos + j); got != i {
+				t.Fatalf("Record(%d) = %d; want %d", pos+j, got, i)
+			}
+		}
+		pos += len(seq)
+	}
+	if x.Record(-1) != -1 || x.Record(len(concat)) != -1 {
+		t.Error("Record out of range did not return -1")
+	}
+
+	for pos := range concat {
+		want := strings.ToUpper(concat[pos : pos+1])[0]
+		if strings.IndexByte("ACGT", want) < 0 {
+			want = 'N'
+		}
+		if got := x.At(pos); got != want {
+			t.Fatalf("At(%d) = %c; want %c", pos, got, want)
+		}
+	}
+
+	lo, hi := 100, 2000
+	for _, base := range []byte("ACGTN") {
+		want := 0
+		for pos := lo; pos < hi; pos++ {
+			if x.At(pos) == base {
+				want++
+			}
+		}
+		if got := x.Count(base, lo, hi); got != want {
+			t.Errorf("Count(%c, %d, %d) = %d; want %d", base, lo, hi, got, want)
+		}
+	}
+}
+
+func TestBuildFASTQ(t *testing.T) {
+	x, err := Build(strings.NewReader("@read1\nACGU\n+\nIIII\n@read2\nnN\n+\n##\n"))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if x.Name(1) != "read2" || x.Count('T', 0, 4) != 1 || x.Count('N', 0, x.Len()) != 2 {
+		t.Errorf("unexpected index: name %q, T count %d, N count %d",
+			x.Name(1), x.Count('T', 0, 4), x.Count('N', 0, x.Len()))
+	}
+}
+
+func TestBuildInvalidCharacter(t *testing.T) {
+	if _, err := Build(strings.NewReader(">a\nAC1GT\n")); err == nil {
+		t.Error("Build with digit in sequence returned no error")
+	}
+}
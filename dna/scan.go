@@ -0,0 +1,190 @@
+// Package dna builds succincter indexes over nucleotide sequences read from FASTA and
+// FASTQ files. Input is streamed: only the bitvectors under construction and one
+// buffered line fragment are held in memory.
+package dna
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Scan streams the records of a FASTA or FASTQ file, detected from the first
+// non-blank line. For each record it calls record with the header (without the '>'
+// or '@' marker), then bases with successive chunks of the record's sequence, with
+// line breaks removed. Chunks alias an internal buffer and are only valid during the
+// call. FASTQ quality lines are skipped.
+func Scan(r io.Reader, record func(name string), bases func(chunk []byte)) error {
+	s := &scanner{br: bufio.NewReaderSize(r, 64<<10), record: record, bases: bases}
+	first, err := s.skipBlankLines()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	switch first {
+	case '>':
+		return s.scanFASTA()
+	case '@':
+		return s.scanFASTQ()
+	}
+	return fmt.Errorf("dna: input is neither FASTA nor FASTQ (starts with %q)", first)
+}
+
+type scanner struct {
+	br     *bufio.Reader
+	record func(string)
+	bases  func([]byte)
+}
+
+func (s *scanner) scanFASTA() error {
+	for {
+		if err := s.header('>'); err != nil {
+			return err
+		}
+		for {
+			c, err := s.peek()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if c == '>' {
+				break
+			}
+			if c == ';' { // legacy comment line
+				_, err = s.line(func([]byte) {})
+			} else {
+				_, err = s.line(s.bases)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *scanner) scanFASTQ() error {
+	for {
+		if err := s.header('@'); err != nil {
+			return err
+		}
+		seqLen := 0
+		for {
+			c, err := s.peek()
+			if err == io.EOF {
+				return errors.New("dna: FASTQ record ends before '+' line")
+			}
+			if err != nil {
+				return err
+			}
+			if c == '+' {
+				break
+			}
+			n, err := s.line(s.bases)
+			if err != nil {
+				return err
+			}
+			seqLen += n
+		}
+		if _, err := s.line(func([]byte) {}); err != nil {
+			return err
+		}
+		// Quality lines may start with '@' or '+', so they are delimited by length.
+		qualLen := 0
+		for qualLen < seqLen {
+			n, err := s.line(func([]byte) {})
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				if _, err := s.peek(); err == io.EOF {
+					return errors.New("dna: FASTQ quality shorter than sequence")
+				}
+			}
+			qualLen += n
+		}
+		if qualLen > seqLen {
+			return errors.New("dna: FASTQ quality longer than sequence")
+		}
+		if seqLen == 0 {
+			// An empty sequence is followed by an empty quality line.
+			if c, err := s.peek(); err == nil && (c == '\n' || c == '\r') {
+				if _, err := s.line(func([]byte) {}); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := s.skipBlankLines(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// header reads a header line starting with marker and reports it.
+func (s *scanner) header(marker byte) error {
+	var name []byte
+	if _, err := s.line(func(chunk []byte) { name = append(name, chunk...) }); err != nil {
+		return err
+	}
+	if len(name) == 0 || name[0] != marker {
+		return fmt.Errorf("dna: expected header starting with %q, got %q", marker, name)
+	}
+	s.record(string(bytes.TrimSpace(name[1:])))
+	return nil
+}
+
+// line consumes one line, passing its content without the line break to fn in one or
+// more chunks, and returns the content length. A final line without a line break is
+// accepted.
+func (s *scanner) line(fn func([]byte)) (int, error) {
+	n := 0
+	for {
+		chunk, err := s.br.ReadSlice('\n')
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return n, err
+		}
+		done := err != bufio.ErrBufferFull
+		if done {
+			chunk = bytes.TrimRight(chunk, "\r\n")
+		}
+		if len(chunk) > 0 {
+			fn(chunk)
+			n += len(chunk)
+		}
+		if done {
+			return n, nil
+		}
+	}
+}
+
+// peek returns the next byte without consuming it.
+func (s *scanner) peek() (byte, error) {
+	b, err := s.br.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// skipBlankLines consumes empty lines and returns the first byte of the next line.
+func (s *scanner) skipBlankLines() (byte, error) {
+	for {
+		c, err := s.peek()
+		if err != nil {
+			return 0, err
+		}
+		if c != '\n' && c != '\r' {
+			return c, nil
+		}
+		if _, err := s.br.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package dna
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type scannedRecord struct {
+	name string
+	seq  string
+}
+
+func scanAll(t *testing.T, input string) ([]scannedRecord, error) {
+	t.Helper()
+	var records []scannedRecord
+	err := Scan(strings.NewReader(input), func(name string) {
+		records = append(records, scannedRecord{name: name})
+	}, func(chunk []byte) {
+		records[len(records)-1].seq += string(chunk)
+	})
+	return records, err
+}
+
+func TestScan(t *testing.T) {
+	long := strings.Repeat("ACGT", 40_000) // longer than the read buffer
+	tests := []struct {
+		name  string
+		input string
+		want  []scannedRecord
+	}{
+		{"empty", "", nil},
+		{"fasta", ">chr1 test\nACGT\nNNac\n>chr2\r\nGG\r\n", []scannedRecord{{"chr1 test", "ACGTNNac"}, {"chr2", "GG"}}},
+		{"fasta no final newline", ">a\nAC\nGT", []scannedRecord{{"a", "ACGT"}}},
+		{"fasta blank and comment lines", "\n>a\nAC\n\n;comment\nGT\n>b\n>c\nT\n", []scannedRecord{{"a", "ACGT"}, {"b", ""}, {"c", "T"}}},
+		{"fasta long line", ">long\n" + long + "\n", []scannedRecord{{"long", long}}},
+		{"fastq", "@r1\nACGT\n+\nIIII\n@r2\nGGN\n+r2\n@+I\n", []scannedRecord{{"r1", "ACGT"}, {"r2", "GGN"}}},
+		{"fastq multiline", "@r1\nAC\nGT\n+\nII\nII\n@r2\nA\n+\n!", []scannedRecord{{"r1", "ACGT"}, {"r2", "A"}}},
+		{"fastq empty read", "@r1\n+\n\n@r2\nA\n+\nI\n", []scannedRecord{{"r1", ""}, {"r2", "A"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanAll(t, tt.input)
+			if err != nil {
+				t.Fatalf("Scan returned error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Scan = %.80v; want %.80v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanErrors(t *testing.T) {
+	for _, input := range []string{
+		"ACGT\n",
+		"@r1\nACGT\n",
+		"@r1\nACGT\n+\nII\n",
+		"@r1\nACGT\n+\nIIIII\n",
+		"@r1\nA\n+\nI\nbad\n",
+	} {
+		if _, err := scanAll(t, input); err == nil {
+			t.Errorf("Scan(%q) returned no error", input)
+		}
+	}
+}
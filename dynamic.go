@@ -0,0 +1,502 @@
+package succincter
+
+import "github.com/shaia/succincter/internal"
+
+// dynamicLeafWords is the maximum chunk size, in words, held by one tree node.
+// A chunk that grows past it is split in half on a word boundary. A chunk that
+// shrinks below dynamicMinWords is merged with a neighbour, so memory stays
+// proportional to Len.
+const (
+	dynamicLeafWords = 8
+	dynamicMinWords  = dynamicLeafWords / 4
+)
+
+// DynamicBitvector is a mutable bitvector supporting Insert, Delete and Set alongside
+// Rank and Select, all in O(log n) time.
+//
+// Bits are stored in word-sized chunks kept in an AVL tree ordered by position, with
+// each node caching the bit and 1-bit counts of its subtree. Unlike Succincter it is
+// not safe for concurrent use; call Freeze to obtain an immutable Succincter for
+// lock-free readers.
+type DynamicBitvector struct {
+	root *dynamicNode
+}
+
+type dynamicNode struct {
+	left, right *dynamicNode
+	height      int
+
+	words []uint64 // chunk bits, least significant first
+	n     int      // bits in this chunk
+	ones  int      // 1-bits in this chunk
+
+	size  int // bits in this subtree
+	count int // 1-bits in this subtree
+}
+
+// NewDynamicBitvector returns an empty DynamicBitvector. The zero value is also ready to use.
+func NewDynamicBitvector() *DynamicBitvector {
+	return &DynamicBitvector{}
+}
+
+// Len returns the number of bits.
+func (d *DynamicBitvector) Len() int {
+	return d.root.sizeOf()
+}
+
+// Ones returns the total number of 1-bits.
+func (d *DynamicBitvector) Ones() int {
+	return d.root.countOf()
+}
+
+// Get returns the bit at pos. Panics if pos is outside [0, Len()).
+func (d *DynamicBitvector) Get(pos int) bool {
+	d.checkIndex(pos, d.Len())
+	node := d.root
+	for {
+		leftSize := node.left.sizeOf()
+		switch {
+		case pos < leftSize:
+			node = node.left
+		case pos < leftSize+node.n:
+			pos -= leftSize
+			return node.words[pos/64]&(uint64(1)<<uint(pos%64)) != 0
+		default:
+			pos -= leftSize + node.n
+			node = node.right
+		}
+	}
+}
+
+// Rank returns the count of 1-bits before position pos. O(log n) time.
+// Returns 0 for pos <= 0 and Ones() for pos >= Len().
+func (d *DynamicBitvector) Rank(pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	if pos >= d.Len() {
+		return d.Ones()
+	}
+	rank := 0
+	node := d.root
+	for {
+		leftSize := node.left.sizeOf()
+		switch {
+		case pos < leftSize:
+			node = node.left
+		case pos <= leftSize+node.n:
+			return rank + node.left.countOf() + chunkRank(node.words, pos-leftSize)
+		default:
+			rank += node.left.countOf() + node.ones
+			pos -= leftSize + node.n
+			node = node.right
+		}
+	}
+}
+
+// Select returns the position of the rank-th 1-bit (1-indexed). O(log n) time.
+// Returns -1 for invalid ranks.
+func (d *DynamicBitvector) Select(rank int) int {
+	if rank <= 0 || rank > d.Ones() {
+		return -1
+	}
+	pos := 0
+	node := d.root
+	for {
+		leftCount := node.left.countOf()
+		switch {
+		case rank <= leftCount:
+			node = node.left
+		case rank <= leftCount+node.ones:
+			return pos + node.left.sizeOf() + chunkSelect(node.words, rank-leftCount)
+		default:
+			rank -= leftCount + node.ones
+			pos += node.left.sizeOf() + node.n
+			node = node.right
+		}
+	}
+}
+
+// Insert inserts bit at pos, shifting later bits up by one. pos may equal Len() to append.
+// Panics if pos is outside [0, Len()].
+func (d *DynamicBitvector) Insert(pos int, bit bool) {
+	d.checkIndex(pos, d.Len()+1)
+	if d.root == nil {
+		d.root = newDynamicNode(make([]uint64, 0, dynamicLeafWords+1), 0)
+	}
+	d.root = d.root.insert(pos, bit)
+}
+
+// Delete removes the bit at pos, shifting later bits down by one.
+// Panics if pos is outside [0, Len()).
+func (d *DynamicBitvector) Delete(pos int) {
+	d.checkIndex(pos, d.Len())
+	d.root = d.root.delete(pos)
+	if d.Len() > 0 {
+		d.compact(min(pos, d.Len()-1))
+	}
+}
+
+// compact merges the chunk holding pos into a neighbour if it has fallen below
+// dynamicMinWords words: it absorbs its successor, or, as the last chunk, is
+// absorbed by its predecessor. The merged chunk is split again if it is too large.
+func (d *DynamicBitvector) compact(pos int) {
+	start, node := d.root.chunkAt(pos)
+	if len(node.words) >= dynamicMinWords {
+		return
+	}
+	switch {
+	case start+node.n < d.Len():
+		var next *dynamicNode
+		d.root, next = d.root.removeChunk(start + node.n)
+		d.root = d.root.extendChunk(start, next.words, next.n)
+	case start > 0:
+		prevStart, _ := d.root.chunkAt(start - 1)
+		d.root, node = d.root.removeChunk(start)
+		d.root = d.root.extendChunk(prevStart, node.words, node.n)
+	}
+}
+
+// Set overwrites the bit at pos. Panics if pos is outside [0, Len()).
+func (d *DynamicBitvector) Set(pos int, bit bool) {
+	d.checkIndex(pos, d.Len())
+	d.root.set(pos, bit)
+}
+
+// Freeze returns an immutable Succincter with the current contents. The
+// DynamicBitvector remains usable and later changes do not affect the result.
+func (d *DynamicBitvector) Freeze() *Succincter {
+	b := NewBuilder(d.Len())
+	d.root.walk(func(n *dynamicNode) {
+		for i := 0; i < n.n; i += 64 {
+			b.AppendWord(n.words[i/64], min(64, n.n-i))
+		}
+	})
+	return b.Build()
+}
+
+func (d *DynamicBitvector) checkIndex(pos, limit int) {
+	if pos < 0 || pos >= limit {
+		panic("succincter: DynamicBitvector position out of range")
+	}
+}
+
+func newDynamicNode(words []uint64, n int) *dynamicNode {
+	node := &dynamicNode{words: words, n: n}
+	for _, w := range words {
+		node.ones += internal.Popcount(w)
+	}
+	node.update()
+	return node
+}
+
+func (n *dynamicNode) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *dynamicNode) countOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+func (n *dynamicNode) heightOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *dynamicNode) update() {
+	n.height = 1 + max(n.left.heightOf(), n.right.heightOf())
+	n.size = n.left.sizeOf() + n.n + n.right.sizeOf()
+	n.count = n.left.countOf() + n.ones + n.right.countOf()
+}
+
+func (n *dynamicNode) rotateRight() *dynamicNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *dynamicNode) rotateLeft() *dynamicNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// balance restores the AVL invariant at n after a child changed height by at most one.
+func (n *dynamicNode) balance() *dynamicNode {
+	n.update()
+	switch diff := n.left.heightOf() - n.right.heightOf(); {
+	case diff > 1:
+		if n.left.left.heightOf() < n.left.right.heightOf() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case diff < -1:
+		if n.right.right.heightOf() < n.right.left.heightOf() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *dynamicNode) insert(pos int, bit bool) *dynamicNode {
+	leftSize := n.left.sizeOf()
+	switch {
+	case pos < leftSize:
+		n.left = n.left.insert(pos, bit)
+	case pos <= leftSize+n.n:
+		n.words = chunkInsert(n.words, n.n, pos-leftSize, bit)
+		n.n++
+		if bit {
+			n.ones++
+		}
+		n.splitIfFull()
+	default:
+		n.right = n.right.insert(pos-leftSize-n.n, bit)
+	}
+	return n.balance()
+}
+
+// splitIfFull splits a chunk larger than dynamicLeafWords on a word boundary; the
+// upper half becomes this node's successor.
+func (n *dynamicNode) splitIfFull() {
+	if len(n.words) <= dynamicLeafWords {
+		return
+	}
+	half := len(n.words) / 2
+	upper := make([]uint64, len(n.words)-half, dynamicLeafWords+1)
+	copy(upper, n.words[half:])
+	sibling := newDynamicNode(upper, n.n-half*64)
+	n.words = n.words[:half]
+	n.n = half * 64
+	n.ones -= sibling.ones
+	n.right = insertMin(n.right, sibling)
+}
+
+// chunkAt returns the node whose chunk holds pos and the position where that chunk
+// starts. pos must be in [0, size).
+func (n *dynamicNode) chunkAt(pos int) (int, *dynamicNode) {
+	start := 0
+	for {
+		leftSize := n.left.sizeOf()
+		switch {
+		case pos < leftSize:
+			n = n.left
+		case pos < leftSize+n.n:
+			return start + leftSize, n
+		default:
+			start += leftSize + n.n
+			pos -= leftSize + n.n
+			n = n.right
+		}
+	}
+}
+
+// removeChunk unlinks the node whose chunk starts at start and returns the new
+// subtree root and the removed node.
+func (n *dynamicNode) removeChunk(start int) (*dynamicNode, *dynamicNode) {
+	leftSize := n.left.sizeOf()
+	var removed *dynamicNode
+	switch {
+	case start < leftSize:
+		n.left, removed = n.left.removeChunk(start)
+	case start == leftSize:
+		return n.remove(), n
+	default:
+		n.right, removed = n.right.removeChunk(start - leftSize - n.n)
+	}
+	return n.balance(), removed
+}
+
+// extendChunk appends m bits from words to the chunk starting at start, splitting it
+// if it grows too large.
+func (n *dynamicNode) extendChunk(start int, words []uint64, m int) *dynamicNode {
+	leftSize := n.left.sizeOf()
+	switch {
+	case start < leftSize:
+		n.left = n.left.extendChunk(start, words, m)
+	case start == leftSize:
+		for i := 0; i < m; i += 64 {
+			n.words = chunkAppend(n.words, n.n, words[i/64], min(64, m-i))
+			n.n += min(64, m-i)
+			n.ones += internal.Popcount(words[i/64])
+		}
+		n.splitIfFull()
+	default:
+		n.right = n.right.extendChunk(start-leftSize-n.n, words, m)
+	}
+	return n.balance()
+}
+
+func insertMin(root, node *dynamicNode) *dynamicNode {
+	if root == nil {
+		return node
+	}
+	root.left = insertMin(root.left, node)
+	return root.balance()
+}
+
+func (n *dynamicNode) delete(pos int) *dynamicNode {
+	leftSize := n.left.sizeOf()
+	switch {
+	case pos < leftSize:
+		n.left = n.left.delete(pos)
+	case pos < leftSize+n.n:
+		var bit bool
+		n.words, bit = chunkDelete(n.words, n.n, pos-leftSize)
+		n.n--
+		if bit {
+			n.ones--
+		}
+		if n.n == 0 {
+			return n.remove()
+		}
+	default:
+		n.right = n.right.delete(pos - leftSize - n.n)
+	}
+	return n.balance()
+}
+
+// remove unlinks n from its subtree and returns the new subtree root.
+func (n *dynamicNode) remove() *dynamicNode {
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+	successor, right := removeMin(n.right)
+	successor.left = n.left
+	successor.right = right
+	return successor.balance()
+}
+
+func removeMin(n *dynamicNode) (*dynamicNode, *dynamicNode) {
+	if n.left == nil {
+		return n, n.right
+	}
+	first, left := removeMin(n.left)
+	n.left = left
+	return first, n.balance()
+}
+
+func (n *dynamicNode) set(pos int, bit bool) {
+	leftSize := n.left.sizeOf()
+	switch {
+	case pos < leftSize:
+		n.left.set(pos, bit)
+	case pos < leftSize+n.n:
+		pos -= leftSize
+		mask := uint64(1) << uint(pos%64)
+		old := n.words[pos/64]&mask != 0
+		if old == bit {
+			return
+		}
+		n.words[pos/64] ^= mask
+		if bit {
+			n.ones++
+		} else {
+			n.ones--
+		}
+	default:
+		n.right.set(pos-leftSize-n.n, bit)
+	}
+	n.update()
+}
+
+func (n *dynamicNode) walk(fn func(*dynamicNode)) {
+	if n == nil {
+		return
+	}
+	n.left.walk(fn)
+	fn(n)
+	n.right.walk(fn)
+}
+
+// chunkRank returns the number of 1-bits before pos in words.
+func chunkRank(words []uint64, pos int) int {
+	rank := 0
+	for i := 0; i < pos/64; i++ {
+		rank += internal.Popcount(words[i])
+	}
+	if off := pos % 64; off != 0 {
+		rank += internal.Popcount(words[pos/64] & ((uint64(1) << uint(off)) - 1))
+	}
+	return rank
+}
+
+// chunkSelect returns the position of the rank-th 1-bit in words; the bit must exist.
+func chunkSelect(words []uint64, rank int) int {
+	for i, w := range words {
+		c := internal.Popcount(w)
+		if rank <= c {
+			return i*64 + internal.SelectInBlock(w, rank)
+		}
+		rank -= c
+	}
+	return -1
+}
+
+// chunkInsert inserts bit at pos in a chunk holding n bits, growing words if needed.
+func chunkInsert(words []uint64, n, pos int, bit bool) []uint64 {
+	if n%64 == 0 {
+		words = append(words, 0)
+	}
+	wi, off := pos/64, uint(pos%64)
+	carry := words[wi] >> 63
+	low := words[wi] & ((uint64(1) << off) - 1)
+	words[wi] = low | (words[wi]&^low)<<1
+	if bit {
+		words[wi] |= uint64(1) << off
+	}
+	for j := wi + 1; j < len(words); j++ {
+		next := words[j] >> 63
+		words[j] = words[j]<<1 | carry
+		carry = next
+	}
+	return words
+}
+
+// chunkAppend appends the low nbits of w, whose higher bits must be zero, to a chunk
+// holding n bits.
+func chunkAppend(words []uint64, n int, w uint64, nbits int) []uint64 {
+	off := uint(n % 64)
+	if off == 0 {
+		return append(words, w)
+	}
+	words[len(words)-1] |= w << off
+	if int(off)+nbits > 64 {
+		words = append(words, w>>(64-off))
+	}
+	return words
+}
+
+// chunkDelete removes the bit at pos from a chunk holding n bits and reports its value.
+func chunkDelete(words []uint64, n, pos int) ([]uint64, bool) {
+	wi, off := pos/64, uint(pos%64)
+	bit := words[wi]&(uint64(1)<<off) != 0
+	low := words[wi] & ((uint64(1) << off) - 1)
+	words[wi] = low | (words[wi]>>(off+1))<<off
+	for j := wi + 1; j < len(words); j++ {
+		words[j-1] |= (words[j] & 1) << 63
+		words[j] >>= 1
+	}
+	if (n-1)%64 == 0 {
+		words = words[:len(words)-1]
+	}
+	return words, bit
+}
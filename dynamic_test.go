@@ -0,0 +1,173 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func checkDynamic(t *testing.T, d *DynamicBitvector, model []bool) {
+	t.Helper()
+	if d.Len() != len(model) {
+		t.Fatalf("Len() = %d; want %d", d.Len(), len(model))
+	}
+	rank := 0
+	for i, v := range model {
+		if got := d.Rank(i); got != rank {
+			t.Fatalf("Rank(%d) = %d; want %d", i, got, rank)
+		}
+		if got := d.Get(i); got != v {
+			t.Fatalf("Get(%d) = %v; want %v", i, got, v)
+		}
+		if v {
+			rank++
+			if got := d.Select(rank); got != i {
+				t.Fatalf("Select(%d) = %d; want %d", rank, got, i)
+			}
+		}
+	}
+	if d.Ones() != rank {
+		t.Fatalf("Ones() = %d; want %d", d.Ones(), rank)
+	}
+	if got := d.Rank(len(model) + 10); got != rank {
+		t.Fatalf("Rank past end = %d; want %d", got, rank)
+	}
+	if got := d.Select(rank + 1); got != -1 {
+		t.Fatalf("Select(%d) = %d; want -1", rank+1, got)
+	}
+}
+
+func TestDynamicBitvectorRandomOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	d := NewDynamicBitvector()
+	var model []bool
+
+	for step := 0; step < 6000; step++ {
+		op := rng.Intn(10)
+		switch {
+		case op < 5 || len(model) == 0:
+			pos := rng.Intn(len(model) + 1)
+			bit := rng.Intn(2) == 0
+			d.Insert(pos, bit)
+			model = slices.Insert(model, pos, bit)
+		case op < 8:
+			pos := rng.Intn(len(model))
+			d.Delete(pos)
+			model = slices.Delete(model, pos, pos+1)
+		default:
+			pos := rng.Intn(len(model))
+			bit := rng.Intn(2) == 0
+			d.Set(pos, bit)
+			model[pos] = bit
+		}
+		if step%500 == 0 {
+			checkDynamic(t, d, model)
+		}
+	}
+	checkDynamic(t, d, model)
+
+	if !reflect.DeepEqual(d.Freeze(), FromBools(model)) {
+		t.Fatal("Freeze differs from FromBools")
+	}
+}
+
+func TestDynamicBitvectorDrainToEmpty(t *testing.T) {
+	var d DynamicBitvector
+	for i := 0; i < 2000; i++ {
+		d.Insert(i, i%3 == 0)
+	}
+	for d.Len() > 0 {
+		d.Delete(d.Len() / 2)
+	}
+	checkDynamic(t, &d, nil)
+	d.Insert(0, true)
+	checkDynamic(t, &d, []bool{true})
+}
+
+func TestDynamicBitvectorMergesAfterDeletes(t *testing.T) {
+	rng := rand.New(rand.NewSource(31))
+	var d DynamicBitvector
+	var model []bool
+	for i := 0; i < 100_000; i++ {
+		bit := rng.Intn(2) == 0
+		d.Insert(d.Len(), bit)
+		model = append(model, bit)
+	}
+	// Scattered deletes leave every chunk a few bits short of a split; without
+	// merging, nodes would pile up at one per handful of remaining bits.
+	for d.Len() > 3000 {
+		pos := rng.Intn(d.Len())
+		d.Delete(pos)
+		model = append(model[:pos], model[pos+1:]...)
+	}
+	checkDynamic(t, &d, model)
+
+	nodes := 0
+	d.root.walk(func(n *dynamicNode) {
+		nodes++
+		if len(n.words) < dynamicMinWords && d.root.size > n.n {
+			t.Errorf("chunk of %d bits left unmerged among %d bits", n.n, d.Len())
+		}
+	})
+	if maxNodes := d.Len()/(dynamicMinWords*64-63) + 1; nodes > maxNodes {
+		t.Errorf("%d nodes for %d bits; want at most %d", nodes, d.Len(), maxNodes)
+	}
+}
+
+func TestDynamicBitvectorBalanced(t *testing.T) {
+	var d DynamicBitvector
+	n := 200_000
+	for i := 0; i < n; i++ {
+		d.Insert(d.Len(), i%5 == 0)
+	}
+	// 200K bits in chunks of at least 256 bits is under 1024 nodes; an AVL tree
+	// of that size is at most ~1.44*log2(1024) = 14.4 levels deep.
+	if h := d.root.heightOf(); h > 15 {
+		t.Errorf("tree height = %d after sequential appends; want <= 15", h)
+	}
+	if got := d.Rank(n); got != n/5 {
+		t.Errorf("Rank(%d) = %d; want %d", n, got, n/5)
+	}
+}
+
+func TestDynamicBitvectorImplementsRankSelector(t *testing.T) {
+	var _ RankSelector = (*DynamicBitvector)(nil)
+}
+
+func TestDynamicBitvectorOutOfRange(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(d *DynamicBitvector)
+	}{
+		{"Insert past end", func(d *DynamicBitvector) { d.Insert(2, true) }},
+		{"Delete at end", func(d *DynamicBitvector) { d.Delete(1) }},
+		{"Set negative", func(d *DynamicBitvector) { d.Set(-1, true) }},
+		{"Get at end", func(d *DynamicBitvector) { d.Get(1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDynamicBitvector()
+			d.Insert(0, true)
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn(d)
+		})
+	}
+}
+
+func BenchmarkDynamicBitvectorInsert(b *testing.B) {
+	rng := rand.New(rand.NewSource(14))
+	var d DynamicBitvector
+	for i := 0; i < 1_000_000; i++ {
+		d.Insert(d.Len(), rng.Intn(2) == 0)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Insert(rng.Intn(d.Len()+1), true)
+	}
+}
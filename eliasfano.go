@@ -0,0 +1,157 @@
+package succincter
+
+import (
+	"iter"
+	"math/bits"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// EFSequence stores a non-decreasing sequence of n values in [0, last] using about
+// 2 + log2(last/n) bits per value, such as file offsets of log lines or cumulative
+// sizes. Each value is split into lowBits low bits, kept in a packed array, and the
+// remaining high bits, stored in unary in a Succincter: value i sets bit
+// (high_i + i). Select on that bitvector recovers high_i, and Select0 finds the first
+// value with given high bits.
+type EFSequence struct {
+	upper   *Succincter
+	lower   *internal.PackedInts
+	lowBits int
+	n       int
+	last    uint64
+}
+
+// eliasFanoBuilder fills an EFSequence one value at a time so callers can stream
+// values without materializing them.
+type eliasFanoBuilder struct {
+	ef         *EFSequence
+	upperWords []uint64
+	upperLen   int
+	next       int
+	prev       uint64
+}
+
+// newEliasFanoBuilder prepares for exactly n non-decreasing values, none above last.
+func newEliasFanoBuilder(n int, last uint64) *eliasFanoBuilder {
+	lowBits := 0
+	if n > 0 && last/uint64(n) > 0 {
+		lowBits = bits.Len64(last/uint64(n)) - 1
+	}
+	upperLen := n + int(last>>uint(lowBits)) + 1
+	return &eliasFanoBuilder{
+		ef: &EFSequence{
+			lower:   internal.NewPackedInts(n, lowBits),
+			lowBits: lowBits,
+			n:       n,
+			last:    last,
+		},
+		upperWords: make([]uint64, (upperLen+63)/64),
+		upperLen:   upperLen,
+	}
+}
+
+// push appends v. Panics if v breaks the ordering, exceeds last, or more than n
+// values are pushed.
+func (b *eliasFanoBuilder) push(v uint64) {
+	if b.next >= b.ef.n {
+		panic("succincter: Elias-Fano sequence has more values than declared")
+	}
+	if v < b.prev || v > b.ef.last {
+		panic("succincter: Elias-Fano values must be non-decreasing and within range")
+	}
+	pos := int(v>>uint(b.ef.lowBits)) + b.next
+	b.upperWords[pos/64] |= 1 << uint(pos%64)
+	b.ef.lower.Set(b.next, v)
+	b.prev = v
+	b.next++
+}
+
+// build returns the finished sequence. Panics if fewer than n values were pushed.
+func (b *eliasFanoBuilder) build() *EFSequence {
+	if b.next != b.ef.n {
+		panic("succincter: Elias-Fano sequence has fewer values than declared")
+	}
+	b.ef.upper = fromBitVector(b.upperWords, b.upperLen)
+	return b.ef
+}
+
+// NewEFSequence encodes values. Panics if values are not non-decreasing.
+func NewEFSequence(values []uint64) *EFSequence {
+	last := uint64(0)
+	if len(values) > 0 {
+		last = values[len(values)-1]
+	}
+	b := newEliasFanoBuilder(len(values), last)
+	for _, v := range values {
+		b.push(v)
+	}
+	return b.build()
+}
+
+// Len returns the number of values.
+func (ef *EFSequence) Len() int {
+	return ef.n
+}
+
+// Access returns value i. Panics if i is outside [0, Len()). O(log n) time.
+func (ef *EFSequence) Access(i int) uint64 {
+	if i < 0 || i >= ef.n {
+		panic("succincter: EFSequence index out of range")
+	}
+	high := uint64(ef.upper.Select(i+1) - i)
+	return high<<uint(ef.lowBits) | ef.lower.Get(i)
+}
+
+// NextGEQ returns the index and value of the first value >= x, or (-1, 0) if every
+// value is smaller. Select0 jumps to the bucket of values sharing x's high bits; the
+// scan within the bucket reads consecutive upper bits with NextOne.
+func (ef *EFSequence) NextGEQ(x uint64) (int, uint64) {
+	if ef.n == 0 || x > ef.last {
+		return -1, 0
+	}
+	hx := int(x >> uint(ef.lowBits))
+	i, pos := 0, 0
+	if hx > 0 {
+		pos = ef.upper.Select0(hx) + 1
+		i = pos - hx
+	}
+	for ; i < ef.n; i++ {
+		pos = ef.upper.NextOne(pos)
+		v := uint64(pos-i)<<uint(ef.lowBits) | ef.lower.Get(i)
+		if v >= x {
+			return i, v
+		}
+		pos++
+	}
+	return -1, 0
+}
+
+// PrevLEQ returns the index and value of the last value <= x, or (-1, 0) if every
+// value is larger.
+func (ef *EFSequence) PrevLEQ(x uint64) (int, uint64) {
+	i := ef.n
+	if x < ef.last {
+		// x+1 <= last, so some value is >= x+1.
+		i, _ = ef.NextGEQ(x + 1)
+	}
+	if i == 0 {
+		return -1, 0
+	}
+	return i - 1, ef.Access(i - 1)
+}
+
+// All iterates the values in order with their indexes. Consecutive values are
+// decoded by scanning the upper bits, so a full pass costs O(n) rather than n
+// selects.
+func (ef *EFSequence) All() iter.Seq2[int, uint64] {
+	return func(yield func(int, uint64) bool) {
+		pos := 0
+		for i := 0; i < ef.n; i++ {
+			pos = ef.upper.NextOne(pos)
+			if !yield(i, uint64(pos-i)<<uint(ef.lowBits)|ef.lower.Get(i)) {
+				return
+			}
+			pos++
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package succincter
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestEFSequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	cases := map[string][]uint64{
+		"empty":  nil,
+		"single": {42},
+		"zeros":  {0, 0, 0},
+		"max":    {0, ^uint64(0)},
+		"dense":  nil,
+		"sparse": nil,
+	}
+	for i := 0; i < 1000; i++ {
+		cases["dense"] = append(cases["dense"], uint64(i+rng.Intn(2)))
+		cases["sparse"] = append(cases["sparse"], uint64(rng.Int63n(1<<40)))
+	}
+	slices.Sort(cases["dense"])
+	slices.Sort(cases["sparse"])
+
+	for name, values := range cases {
+		t.Run(name, func(t *testing.T) {
+			ef := NewEFSequence(values)
+			if ef.Len() != len(values) {
+				t.Fatalf("Len() = %d; want %d", ef.Len(), len(values))
+			}
+			for i, v := range values {
+				if got := ef.Access(i); got != v {
+					t.Fatalf("Access(%d) = %d; want %d", i, got, v)
+				}
+			}
+			var all []uint64
+			for i, v := range ef.All() {
+				if i != len(all) {
+					t.Fatalf("All yielded index %d at step %d", i, len(all))
+				}
+				all = append(all, v)
+			}
+			if len(all) != len(values) || (len(values) > 0 && !slices.Equal(all, values)) {
+				t.Fatalf("All() = %v; want %v", all, values)
+			}
+
+			probes := []uint64{0, 1, 41, 42, 43, ^uint64(0)}
+			for _, v := range values {
+				probes = append(probes, v, v+1)
+				if v > 0 {
+					probes = append(probes, v-1)
+				}
+			}
+			for _, x := range probes {
+				wantIdx, _ := slices.BinarySearch(values, x)
+				var wantVal uint64
+				if wantIdx < len(values) {
+					wantVal = values[wantIdx]
+				} else {
+					wantIdx = -1
+				}
+				gotIdx, gotVal := ef.NextGEQ(x)
+				if gotIdx != wantIdx || gotVal != wantVal {
+					t.Fatalf("NextGEQ(%d) = (%d, %d); want (%d, %d)", x, gotIdx, gotVal, wantIdx, wantVal)
+				}
+
+				// The last value <= x sits just before the first value > x.
+				wantIdx = len(values)
+				for i, v := range values {
+					if v > x {
+						wantIdx = i
+						break
+					}
+				}
+				wantIdx--
+				wantVal = 0
+				if wantIdx >= 0 {
+					wantVal = values[wantIdx]
+				}
+				gotIdx, gotVal = ef.PrevLEQ(x)
+				if gotIdx != wantIdx || gotVal != wantVal {
+					t.Fatalf("PrevLEQ(%d) = (%d, %d); want (%d, %d)", x, gotIdx, gotVal, wantIdx, wantVal)
+				}
+			}
+		})
+	}
+}
+
+func TestEFSequencePanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"decreasing", func() { NewEFSequence([]uint64{5, 4}) }},
+		{"builder decreasing", func() {
+			b := newEliasFanoBuilder(2, 10)
+			b.push(5)
+			b.push(4)
+		}},
+		{"Access at end", func() { NewEFSequence([]uint64{1}).Access(1) }},
+		{"Access negative", func() { NewEFSequence([]uint64{1}).Access(-1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func BenchmarkEFSequenceNextGEQ(b *testing.B) {
+	rng := rand.New(rand.NewSource(29))
+	values := make([]uint64, 1_000_000)
+	for i := range values {
+		values[i] = uint64(rng.Int63n(1 << 40))
+	}
+	slices.Sort(values)
+	ef := NewEFSequence(values)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ef.NextGEQ(uint64(rng.Int63n(1 << 40)))
+	}
+}
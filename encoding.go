@@ -0,0 +1,126 @@
+package succincter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Binary format shared by the serializable structures in this package:
+//
+//	magic "SCCT" | kind byte | format version byte | kind-specific payload
+//
+// Integers are little-endian uint64. A bitvector is stored as its length in bits
+// followed by its words; rank directories are rebuilt on load rather than stored.
+const (
+	encodingMagic   = "SCCT"
+	encodingVersion = 1
+
+	kindSuccincter byte = 1
+	kindK2Tree     byte = 2
+)
+
+// MarshalBinary encodes the bitvector. The rank directory is not stored; it is
+// rebuilt by UnmarshalBinary in O(n/64) time.
+func (s *Succincter) MarshalBinary() ([]byte, error) {
+	buf := appendHeader(nil, kindSuccincter)
+	return appendBitvector(buf, s), nil
+}
+
+// UnmarshalBinary replaces s with a bitvector encoded by MarshalBinary.
+func (s *Succincter) UnmarshalBinary(data []byte) error {
+	d, err := newDecoder(data, kindSuccincter)
+	if err != nil {
+		return err
+	}
+	bv, err := d.bitvector()
+	if err != nil {
+		return err
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	*s = *bv
+	return nil
+}
+
+func appendHeader(buf []byte, kind byte) []byte {
+	buf = append(buf, encodingMagic...)
+	return append(buf, kind, encodingVersion)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, v)
+}
+
+func appendBitvector(buf []byte, s *Succincter) []byte {
+	buf = appendUint64(buf, uint64(s.length))
+	for _, w := range s.data[:(s.length+63)/64] {
+		buf = appendUint64(buf, w)
+	}
+	return buf
+}
+
+// decoder reads a payload written with appendHeader and the append helpers.
+type decoder struct {
+	data []byte
+}
+
+func newDecoder(data []byte, kind byte) (*decoder, error) {
+	if len(data) < len(encodingMagic)+2 || string(data[:len(encodingMagic)]) != encodingMagic {
+		return nil, errors.New("succincter: not a succincter encoding")
+	}
+	data = data[len(encodingMagic):]
+	if data[0] != kind {
+		return nil, fmt.Errorf("succincter: encoding holds kind %d, want %d", data[0], kind)
+	}
+	if data[1] != encodingVersion {
+		return nil, fmt.Errorf("succincter: unsupported encoding version %d", data[1])
+	}
+	return &decoder{data: data[2:]}, nil
+}
+
+func (d *decoder) uint64() (uint64, error) {
+	if len(d.data) < 8 {
+		return 0, errors.New("succincter: truncated encoding")
+	}
+	v := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return v, nil
+}
+
+// int reads a uint64 that must fit a non-negative int no larger than limit.
+func (d *decoder) int(limit uint64) (int, error) {
+	v, err := d.uint64()
+	if err != nil {
+		return 0, err
+	}
+	if v > limit {
+		return 0, fmt.Errorf("succincter: encoded value %d out of range", v)
+	}
+	return int(v), nil
+}
+
+func (d *decoder) bitvector() (*Succincter, error) {
+	length, err := d.int(uint64(len(d.data)) * 8)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]uint64, (length+63)/64)
+	for i := range words {
+		if words[i], err = d.uint64(); err != nil {
+			return nil, err
+		}
+	}
+	if tail := length % 64; tail != 0 && words[len(words)-1]>>uint(tail) != 0 {
+		return nil, errors.New("succincter: encoded bitvector has bits beyond its length")
+	}
+	return fromBitVector(words, length), nil
+}
+
+func (d *decoder) finish() error {
+	if len(d.data) != 0 {
+		return fmt.Errorf("succincter: %d trailing bytes after encoding", len(d.data))
+	}
+	return nil
+}
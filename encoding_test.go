@@ -0,0 +1,53 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	for _, n := range []int{0, 1, 63, 64, 65, 1024, 5000} {
+		want := FromBools(randomBools(rng, n))
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("n=%d: MarshalBinary: %v", n, err)
+		}
+		var got Succincter
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("n=%d: UnmarshalBinary: %v", n, err)
+		}
+		if !reflect.DeepEqual(&got, want) {
+			t.Fatalf("n=%d: round trip differs", n)
+		}
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	data, _ := FromBools([]bool{true, false, true}).MarshalBinary()
+	tree, _ := NewK2Tree(2, [][2]int{{0, 1}}).MarshalBinary()
+	dirty := append([]byte(nil), data...)
+	dirty[len(dirty)-1] = 0xff // bits beyond the length
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte("XXXX"), data[4:]...)},
+		{"wrong kind", tree},
+		{"bad version", append(append([]byte(nil), data[:5]...), append([]byte{99}, data[6:]...)...)},
+		{"truncated", data[:len(data)-3]},
+		{"trailing bytes", append(append([]byte(nil), data...), 0)},
+		{"bits beyond length", dirty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Succincter
+			if err := s.UnmarshalBinary(tt.data); err == nil {
+				t.Error("UnmarshalBinary returned no error")
+			}
+		})
+	}
+}
@@ -9,10 +9,13 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/shaia/succincter"
+	"github.com/shaia/succincter/dna"
 )
 
 type Nucleotide byte
@@ -75,12 +78,71 @@ func main() {
 	fmt.Println("\n--- CpG Island Detection ---")
 	windowSize := 1000
 	threshold := 0.6 // 60% GC content
-	islands := findCpGIslands(guanineIndex, cytosineIndex, total, windowSize, threshold)
+	islands := findCpGIslands(guanineIndex, cytosineIndex, windowSize, threshold)
 	fmt.Printf("Found %d potential CpG islands (>%.0f%% GC in %d bp windows)\n",
 		len(islands), threshold*100, windowSize)
 	if len(islands) > 0 {
 		fmt.Printf("First island at position: %d\n", islands[0])
 	}
+
+	gcIndex := succincter.Or(guanineIndex, cytosineIndex)
+	regions := gcIndex.WindowsAbove(windowSize, int(threshold*float64(windowSize)))
+	fmt.Printf("Merged GC-rich regions (any %d bp window): %d\n", windowSize, len(regions))
+	peak, peakCount := gcIndex.MaxDensityWindow(windowSize)
+	fmt.Printf("Densest %d bp window: [%d, %d) with %.1f%% GC\n",
+		windowSize, peak, peak+windowSize, float64(peakCount)*100/float64(windowSize))
+
+	// Suffix array queries over the first 100 kbp in compressed space
+	fmt.Println("\n--- Compressed Suffix Array (first 100 kbp) ---")
+	start = time.Now()
+	csa := succincter.NewCSA(sequence[:100_000], []Nucleotide{A, C, G, T})
+	fmt.Printf("Built in %v\n", time.Since(start))
+	repeatPos, repeatLen := csa.LongestRepeat()
+	fmt.Printf("Longest repeat: %d bp at position %d\n", repeatLen, repeatPos)
+	motif := []Nucleotide{G, A, T, T, A, C, A}
+	occurrences := csa.Locate(motif)
+	fmt.Printf("Occurrences of GATTACA: %d\n", len(occurrences))
+
+	// Count every k-mer at once, merging each with its reverse complement
+	k := 8
+	fmt.Printf("\n--- Canonical %d-mer Counts ---\n", k)
+	start = time.Now()
+	kmers := succincter.NewKmerIndex(sequence, k, true)
+	fmt.Printf("Indexed %d %d-mers (%d distinct) in %v\n",
+		kmers.Total(), k, kmers.Distinct(), time.Since(start))
+	topKmer, topCount := "", 0
+	for kmer, count := range kmers.All() {
+		if count > topCount {
+			topKmer, topCount = kmer, count
+		}
+	}
+	fmt.Printf("Most frequent: %s (%d times)\n", topKmer, topCount)
+	fmt.Printf("GATTACAG (or CTGTAATC) occurs %d times\n", kmers.Count("GATTACAG"))
+	shown := 0
+	for pos := range kmers.Positions("GATTACAG") {
+		if shown == 3 {
+			break
+		}
+		fmt.Printf("  at position %d\n", pos)
+		shown++
+	}
+
+	// Real data arrives as FASTA: stream it straight into per-base bitvectors
+	fmt.Println("\n--- Streaming FASTA Ingestion ---")
+	fasta := generateFASTA(sequence, 4, 80)
+	start = time.Now()
+	fileIndex, err := dna.Build(fasta)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Indexed %d records (%d bp) in %v\n", fileIndex.NumRecords(), fileIndex.Len(), time.Since(start))
+	for i := 0; i < fileIndex.NumRecords(); i++ {
+		lo, hi := fileIndex.Span(i)
+		fmt.Printf("  %-6s [%7d, %7d)  GC %.2f%%\n", fileIndex.Name(i), lo, hi,
+			float64(fileIndex.Count('G', lo, hi)+fileIndex.Count('C', lo, hi))*100/float64(hi-lo))
+	}
+	fmt.Printf("Position 600000 lies in record %s\n", fileIndex.Name(fileIndex.Record(600_000)))
 }
 
 func generateDNASequence(n int) []Nucleotide {
@@ -92,13 +154,30 @@ func generateDNASequence(n int) []Nucleotide {
 	return sequence
 }
 
-func findCpGIslands(gIndex, cIndex *succincter.Succincter, seqLen, windowSize int, threshold float64) []int {
+// generateFASTA formats the sequence as a multi-record FASTA file with wrapped lines.
+func generateFASTA(sequence []Nucleotide, records, lineWidth int) io.Reader {
+	var b strings.Builder
+	size := len(sequence) / records
+	for r := 0; r < records; r++ {
+		fmt.Fprintf(&b, ">chr%d\n", r+1)
+		part := sequence[r*size : (r+1)*size]
+		for i := 0; i < len(part); i += lineWidth {
+			for _, n := range part[i:min(i+lineWidth, len(part))] {
+				b.WriteByte(byte(n))
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return strings.NewReader(b.String())
+}
+
+func findCpGIslands(gIndex, cIndex *succincter.Succincter, windowSize int, threshold float64) []int {
 	var islands []int
-	for pos := 0; pos+windowSize <= seqLen; pos += windowSize / 2 {
-		gcCount := (gIndex.Rank(pos+windowSize) - gIndex.Rank(pos)) +
-			(cIndex.Rank(pos+windowSize) - cIndex.Rank(pos))
+	step := windowSize / 2
+	gcIndex := succincter.Or(gIndex, cIndex)
+	for i, gcCount := range gcIndex.WindowCounts(windowSize, step) {
 		if float64(gcCount)/float64(windowSize) >= threshold {
-			islands = append(islands, pos)
+			islands = append(islands, i*step)
 		}
 	}
 	return islands
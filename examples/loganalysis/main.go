@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/shaia/succincter"
@@ -69,6 +70,34 @@ func main() {
 		fmt.Printf("   Error %d at position %d: %s\n", i, p, logs[p].Message)
 	}
 
+	// Query 5: ordered severity ranges from one categorical index
+	levelIndex := succincter.NewCategoryIndex(logs, []string{"DEBUG", "INFO", "WARN", "ERROR"},
+		func(e LogEntry) string { return e.Level })
+	warnOrAbove := levelIndex.AtLeast("WARN")
+	fmt.Printf("\n5. WARN or above before position %d: %d; 100th at position %d\n",
+		pos, warnOrAbove.Rank(pos), warnOrAbove.Select(100))
+
+	// Query 6: term search over messages with an inverted index
+	start = time.Now()
+	messageIndex := succincter.NewInvertedIndex(logs, func(e LogEntry) []string {
+		return strings.Fields(strings.ToLower(e.Message))
+	})
+	fmt.Printf("\n6. Message index: %d distinct terms, built in %v\n", messageIndex.Terms(), time.Since(start))
+	matches, err := messageIndex.Query("timeout AND (db-3 OR db-7) AND NOT retrying")
+	if err != nil {
+		fmt.Println("   Query error:", err)
+		return
+	}
+	shown, total := 0, 0
+	for p := range matches {
+		if shown < 3 {
+			fmt.Printf("   position %d [%s]: %s\n", p, logs[p].Level, logs[p].Message)
+			shown++
+		}
+		total++
+	}
+	fmt.Printf("   %d entries match \"timeout AND (db-3 OR db-7) AND NOT retrying\"\n", total)
+
 	// Compare with naive approach
 	fmt.Println("\n--- Performance Comparison ---")
 	comparePerformance(logs, errorIndex)
@@ -76,6 +105,14 @@ func main() {
 
 func generateLogs(n int, errorRate float64) []LogEntry {
 	levels := []string{"DEBUG", "INFO", "WARN"}
+	templates := []string{
+		"user %d logged in",
+		"cache miss for key %d",
+		"connection timeout to db-%d",
+		"connection timeout to db-%d retrying",
+		"disk usage high on node-%d",
+		"request %d completed",
+	}
 	logs := make([]LogEntry, n)
 
 	for i := range logs {
@@ -86,7 +123,7 @@ func generateLogs(n int, errorRate float64) []LogEntry {
 		logs[i] = LogEntry{
 			Timestamp: time.Now().Add(time.Duration(i) * time.Millisecond),
 			Level:     level,
-			Message:   fmt.Sprintf("Log message %d", i),
+			Message:   fmt.Sprintf(templates[rand.Intn(len(templates))], rand.Intn(10)),
 		}
 	}
 	return logs
@@ -107,12 +107,40 @@ func main() {
 			startRank+i, pos, r.Value, r.Unit, r.Timestamp.Format("15:04:05"))
 	}
 
-	// Time-based query: anomalies in a specific hour
+	// Time-based query: anomalies in a specific hour. The time index maps
+	// timestamps to positions, so this works for irregular sampling too.
 	fmt.Println("\n--- Anomalies Between 14:00-15:00 ---")
-	hour14Start := 14 * readingsPerHour
-	hour14End := 15 * readingsPerHour
-	anomaliesInHour := anyAnomalyIndex.Rank(hour14End) - anyAnomalyIndex.Rank(hour14Start)
-	fmt.Printf("Count: %d anomalies\n", anomaliesInHour)
+	timeIndex := succincter.NewTimeIndex(readings, func(r SensorReading) time.Time {
+		return r.Timestamp
+	})
+	day := readings[0].Timestamp.Truncate(24 * time.Hour)
+	from, to := day.Add(14*time.Hour), day.Add(15*time.Hour)
+	fmt.Printf("Count: %d anomalies\n", timeIndex.CountIn(anyAnomalyIndex, from, to))
+	if pos := timeIndex.SelectIn(anyAnomalyIndex, from, to, 1); pos != -1 {
+		fmt.Printf("First: %.1f%s at %s\n", readings[pos].Value, readings[pos].Unit,
+			readings[pos].Timestamp.Format("15:04:05"))
+	}
+
+	// Thresholds chosen at query time: one bit-sliced index over the quantized
+	// value answers any range without building a new index per threshold.
+	fmt.Println("\n--- Ad-hoc Thresholds (Bit-Sliced Index) ---")
+	valueIndex := succincter.NewBitSlicedIndex(readings, func(r SensorReading) int64 {
+		return int64(math.Round(r.Value * 10)) // 0.1°C resolution
+	})
+	for _, band := range [][2]float64{{0, 20}, {20, 40}, {40, 60}, {60, 80}, {80, 120}} {
+		lo, hi := int64(band[0]*10), int64(band[1]*10)
+		fmt.Printf("  [%3.0f, %3.0f)°C: %6d readings\n", band[0], band[1], valueIndex.Count(lo, hi))
+	}
+	hot := valueIndex.Range(850, 1200)
+	if hot.Ones() > 0 {
+		fmt.Printf("  Mean of readings >= 85°C: %.1f°C\n",
+			float64(valueIndex.Sum(hot))/10/float64(hot.Ones()))
+	}
+	fmt.Println("  Hottest 3 readings:")
+	for _, pos := range valueIndex.TopK(3, nil) {
+		fmt.Printf("    %.1f%s at %s\n", readings[pos].Value, readings[pos].Unit,
+			readings[pos].Timestamp.Format("15:04:05"))
+	}
 }
 
 func generateSensorData(n int) []SensorReading {
@@ -77,13 +77,10 @@ func main() {
 	// Premium user pagination
 	fmt.Println("\n--- Premium User Pagination (Page 5, 10 per page) ---")
 	page, pageSize := 5, 10
+	userIndex := succincter.NewIndex(users)
+	userIndex.Register("premium", premiumIndex)
 	startRank := (page-1)*pageSize + 1
-	for i := 0; i < pageSize; i++ {
-		pos := premiumIndex.Select(startRank + i)
-		if pos == -1 {
-			break
-		}
-		u := users[pos]
+	for i, u := range userIndex.Page("premium", page, pageSize) {
 		fmt.Printf("  %2d. %-15s Score: %4d  Online: %v\n",
 			startRank+i, u.Username, u.Score, u.IsOnline)
 	}
@@ -100,18 +97,17 @@ func main() {
 			r[0], r[1], onlineInRange, premiumInRange, highInRange)
 	}
 
-	// Find users with multiple attributes
+	// Find users with multiple attributes by combining indices word-by-word
 	fmt.Println("\n--- Online Premium High-Scorers ---")
-	count := 0
-	for i := 1; i <= onlineIndex.Rank(numUsers) && count < 5; i++ {
-		pos := onlineIndex.Select(i)
-		u := users[pos]
-		if u.IsPremium && u.Score >= 1000 {
-			count++
-			fmt.Printf("  %d. %-15s Score: %d\n", count, u.Username, u.Score)
-		}
+	onlinePremium := succincter.And(onlineIndex, premiumIndex)
+	combined := succincter.And(onlinePremium, highScorerIndex)
+	fmt.Printf("Matching users: %d (online AND premium: %d)\n",
+		combined.Ones(), succincter.CountAnd(onlineIndex, premiumIndex))
+	for i := 1; i <= combined.Ones() && i <= 5; i++ {
+		u := users[combined.Select(i)]
+		fmt.Printf("  %d. %-15s Score: %d\n", i, u.Username, u.Score)
 	}
-	if count == 0 {
+	if combined.Ones() == 0 {
 		fmt.Println("  No users found matching all criteria")
 	}
 
@@ -0,0 +1,80 @@
+package succincter
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// FromBools constructs a Succincter from a []bool without a predicate call per element.
+func FromBools(input []bool) *Succincter {
+	data := make([]uint64, (len(input)+63)/64)
+	for i, v := range input {
+		if v {
+			data[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return fromBitVector(data, len(input))
+}
+
+// FromWords constructs a Succincter over the first nbits bits of words, where bit i
+// is bit i%64 of words[i/64].
+//
+// When words has exactly the required length and no bits set at or beyond nbits,
+// the Succincter uses words directly without copying; the caller must not modify
+// words afterwards. Otherwise the needed prefix is copied and masked.
+// Panics if nbits is negative or exceeds len(words)*64.
+func FromWords(words []uint64, nbits int) *Succincter {
+	if nbits < 0 || nbits > len(words)*64 {
+		panic("succincter: FromWords nbits out of range")
+	}
+	numWords := (nbits + 63) / 64
+	tail := nbits % 64
+
+	if len(words) == numWords && (tail == 0 || words[numWords-1]>>uint(tail) == 0) {
+		return fromBitVector(words, nbits)
+	}
+
+	data := make([]uint64, numWords)
+	copy(data, words)
+	clearTail(data, nbits)
+	return fromBitVector(data, nbits)
+}
+
+// FromPositions constructs a Succincter of length n with 1-bits at the given
+// positions. Positions are expected in ascending order; duplicates are ignored.
+// Panics if a position is outside [0, n).
+func FromPositions(sorted []int, n int) *Succincter {
+	if n < 0 {
+		panic("succincter: FromPositions negative length")
+	}
+	data := make([]uint64, (n+63)/64)
+	for _, p := range sorted {
+		if p < 0 || p >= n {
+			panic("succincter: FromPositions position out of range")
+		}
+		data[p/64] |= 1 << uint(p%64)
+	}
+	return fromBitVector(data, n)
+}
+
+// FromBigInt constructs a Succincter of length n where bit i is bit i of x.
+// Bits of x at or beyond n are dropped. Panics if x is negative or n is negative.
+func FromBigInt(x *big.Int, n int) *Succincter {
+	if x.Sign() < 0 {
+		panic("succincter: FromBigInt negative value")
+	}
+	if n < 0 {
+		panic("succincter: FromBigInt negative length")
+	}
+
+	data := make([]uint64, (n+63)/64)
+	for i, w := range x.Bits() {
+		bitPos := i * bits.UintSize
+		if bitPos >= n {
+			break
+		}
+		data[bitPos/64] |= uint64(w) << uint(bitPos%64)
+	}
+	clearTail(data, n)
+	return fromBitVector(data, n)
+}
@@ -0,0 +1,121 @@
+package succincter
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func randomBools(rng *rand.Rand, n int) []bool {
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = rng.Intn(4) == 0
+	}
+	return out
+}
+
+func TestFromBools(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, n := range []int{0, 1, 64, 65, 2049} {
+		input := randomBools(rng, n)
+		got := FromBools(input)
+		want := NewSuccincter(input, func(b bool) bool { return b })
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("n=%d: FromBools differs from NewSuccincter", n)
+		}
+	}
+}
+
+func TestFromWords(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	words := make([]uint64, 40)
+	for i := range words {
+		words[i] = rng.Uint64()
+	}
+
+	for _, nbits := range []int{0, 1, 63, 64, 100, 1024, 2559, 2560} {
+		input := make([]bool, nbits)
+		for i := range input {
+			input[i] = words[i/64]&(uint64(1)<<uint(i%64)) != 0
+		}
+		got := FromWords(words, nbits)
+		want := FromBools(input)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("nbits=%d: FromWords differs from FromBools", nbits)
+		}
+	}
+
+	t.Run("zero copy", func(t *testing.T) {
+		exact := []uint64{0xFF, 0x1}
+		s := FromWords(exact, 65)
+		if &s.data[0] != &exact[0] {
+			t.Error("FromWords copied words that were safe to share")
+		}
+	})
+
+	t.Run("masks trailing bits", func(t *testing.T) {
+		dirty := []uint64{0xFF}
+		s := FromWords(dirty, 4)
+		if &s.data[0] == &dirty[0] {
+			t.Error("FromWords shared words with bits beyond nbits")
+		}
+		if got := s.Rank(64); got != 4 {
+			t.Errorf("Rank(64) = %d; want 4", got)
+		}
+	})
+
+	t.Run("out of range panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("FromWords with nbits > 64*len(words) did not panic")
+			}
+		}()
+		FromWords([]uint64{0}, 65)
+	})
+}
+
+func TestFromPositions(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	input := randomBools(rng, 3000)
+	var positions []int
+	for i, v := range input {
+		if v {
+			positions = append(positions, i)
+		}
+	}
+
+	got := FromPositions(positions, len(input))
+	want := FromBools(input)
+	if !reflect.DeepEqual(got, want) {
+		t.Error("FromPositions differs from FromBools")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("FromPositions with position >= n did not panic")
+		}
+	}()
+	FromPositions([]int{10}, 10)
+}
+
+func TestFromBigInt(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	input := randomBools(rng, 777)
+
+	x := new(big.Int)
+	for i, v := range input {
+		if v {
+			x.SetBit(x, i, 1)
+		}
+	}
+
+	for _, n := range []int{0, 5, 64, 500, 777, 900} {
+		want := make([]bool, n)
+		copy(want, input)
+		got := FromBigInt(x, n)
+		if !reflect.DeepEqual(got, FromBools(want)) {
+			t.Errorf("n=%d: FromBigInt differs from FromBools", n)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package succincter
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Graph is a static directed graph in compressed sparse row form. Node u's
+// neighbors are entries [offset(u), offset(u+1)) of a packed IntVector, sorted
+// ascending; the n+1 offsets are an Elias-Fano sequence, so each is one Select on
+// its upper bits. Parallel edges are kept.
+type Graph struct {
+	offsets   *EFSequence
+	neighbors *IntVector
+	n         int
+}
+
+// NewGraph builds a graph on nodes [0, n) from (from, to) edges given in any
+// order. Panics if n is negative or an endpoint is outside [0, n).
+func NewGraph(n int, edges iter.Seq2[int, int]) *Graph {
+	if n < 0 {
+		panic("succincter: Graph node count must be non-negative")
+	}
+	var list [][2]int
+	for u, v := range edges {
+		if u < 0 || u >= n || v < 0 || v >= n {
+			panic("succincter: Graph edge endpoint out of range")
+		}
+		list = append(list, [2]int{u, v})
+	}
+	slices.SortFunc(list, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+
+	offsets := newEliasFanoBuilder(n+1, uint64(len(list)))
+	targets := make([]uint64, len(list))
+	e := 0
+	for u := 0; u <= n; u++ {
+		for e < len(list) && list[e][0] < u {
+			targets[e] = uint64(list[e][1])
+			e++
+		}
+		offsets.push(uint64(e))
+	}
+	return &Graph{offsets: offsets.build(), neighbors: NewIntVector(targets), n: n}
+}
+
+// Len returns the number of nodes.
+func (g *Graph) Len() int {
+	return g.n
+}
+
+// Edges returns the number of edges.
+func (g *Graph) Edges() int {
+	return g.neighbors.Len()
+}
+
+// Degree returns the out-degree of u, or 0 if u is out of range.
+func (g *Graph) Degree(u int) int {
+	lo, hi := g.span(u)
+	return hi - lo
+}
+
+// Neighbors iterates the targets of edges leaving u in ascending order.
+func (g *Graph) Neighbors(u int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		lo, hi := g.span(u)
+		for i := lo; i < hi; i++ {
+			if !yield(int(g.neighbors.Get(i))) {
+				return
+			}
+		}
+	}
+}
+
+// HasEdge reports whether an edge (u, v) exists, by binary search over u's
+// neighbors.
+func (g *Graph) HasEdge(u, v int) bool {
+	lo, hi := g.span(u)
+	i := lo + sort.Search(hi-lo, func(k int) bool { return int(g.neighbors.Get(lo+k)) >= v })
+	return i < hi && int(g.neighbors.Get(i)) == v
+}
+
+// span returns the neighbor entries of u, or an empty span if u is out of range.
+func (g *Graph) span(u int) (lo, hi int) {
+	if u < 0 || u >= g.n {
+		return 0, 0
+	}
+	return int(g.offsets.Access(u)), int(g.offsets.Access(u + 1))
+}
@@ -0,0 +1,68 @@
+package succincter
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	for _, n := range []int{0, 1, 10, 500} {
+		adj := make(map[int][]int)
+		var list [][2]int
+		for i := 0; i < 4*n; i++ {
+			u, v := rng.Intn(n), rng.Intn(n)
+			list = append(list, [2]int{u, v})
+			adj[u] = append(adj[u], v)
+		}
+		g := NewGraph(n, func(yield func(int, int) bool) {
+			for _, e := range list {
+				if !yield(e[0], e[1]) {
+					return
+				}
+			}
+		})
+		if g.Len() != n || g.Edges() != len(list) {
+			t.Fatalf("Len, Edges = %d, %d; want %d, %d", g.Len(), g.Edges(), n, len(list))
+		}
+		for u := 0; u < n; u++ {
+			want := slices.Sorted(slices.Values(adj[u]))
+			if g.Degree(u) != len(want) {
+				t.Fatalf("n=%d: Degree(%d) = %d; want %d", n, u, g.Degree(u), len(want))
+			}
+			if got := slices.Collect(g.Neighbors(u)); len(got) != len(want) || (len(want) > 0 && !slices.Equal(got, want)) {
+				t.Fatalf("n=%d: Neighbors(%d) = %v; want %v", n, u, got, want)
+			}
+			for v := 0; v < min(n, 50); v++ {
+				if g.HasEdge(u, v) != slices.Contains(want, v) {
+					t.Fatalf("n=%d: HasEdge(%d, %d) = %v", n, u, v, !slices.Contains(want, v))
+				}
+			}
+		}
+		if g.Degree(-1) != 0 || g.Degree(n) != 0 || g.HasEdge(n, 0) {
+			t.Errorf("n=%d: out-of-range node has edges", n)
+		}
+	}
+}
+
+func TestGraphFromMap(t *testing.T) {
+	// maps.All is an iter.Seq2, so a successor map is a valid edge source.
+	g := NewGraph(4, maps.All(map[int]int{0: 1, 1: 2, 3: 0}))
+	if got := slices.Collect(g.Neighbors(1)); !slices.Equal(got, []int{2}) {
+		t.Errorf("Neighbors(1) = %v; want [2]", got)
+	}
+	if g.Degree(2) != 0 || g.Edges() != 3 {
+		t.Errorf("Degree(2), Edges = %d, %d; want 0, 3", g.Degree(2), g.Edges())
+	}
+}
+
+func TestGraphInvalidEdge(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewGraph with out-of-range endpoint did not panic")
+		}
+	}()
+	NewGraph(2, maps.All(map[int]int{0: 2}))
+}
@@ -0,0 +1,136 @@
+package succincter
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Growable is an append-only bitvector. Bits are stored the way Live stores a
+// version: fixed-size chunks, each its own Succincter, plus a prefix table of 1-bit
+// counts. Appends extend the last (open) chunk and its rank directory in place;
+// existing directory entries never change and a chunk is frozen once full, so each
+// appended bit costs amortized O(1) and earlier history is never rescanned.
+//
+// Len, Rank and Select answer from the Growable's own storage under its lock.
+// Readers that need a stable view without the lock call Snapshot to get an immutable
+// *LiveVersion over everything appended so far. Snapshots share every full chunk
+// and the open chunk's directory with the Growable; only the open chunk's words are
+// copied, and only when its last word is partially filled, so a snapshot never pins
+// a word the writer still mutates and costs O(chunk + n/chunk). Snapshot and the
+// query methods are safe to call concurrently with Append.
+type Growable struct {
+	mu       sync.Mutex
+	v        LiveVersion // chunks[len(chunks)-1] is the open chunk; its words are private
+	snapshot atomic.Pointer[LiveVersion]
+}
+
+// NewGrowable returns an empty Growable. The zero value is also ready to use.
+func NewGrowable() *Growable {
+	return &Growable{}
+}
+
+// Append adds bits to the end of the bitvector.
+func (g *Growable) Append(bits ...bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, bit := range bits {
+		g.appendBit(bit)
+	}
+	g.snapshot.Store(nil)
+}
+
+// AppendFrom adds one bit per element of input, set where predicate returns true.
+// It is a function rather than a method because Go methods cannot take type parameters.
+func AppendFrom[T any](g *Growable, input []T, predicate func(T) bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range input {
+		g.appendBit(predicate(v))
+	}
+	g.snapshot.Store(nil)
+}
+
+// Len returns the number of bits appended so far.
+func (g *Growable) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.v.length
+}
+
+// Rank returns the count of 1-bits before pos among the bits appended so far.
+func (g *Growable) Rank(pos int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.version().Rank(pos)
+}
+
+// Select returns the position of the rank-th 1-bit among the bits appended so far.
+func (g *Growable) Select(rank int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.version().Select(rank)
+}
+
+// Snapshot returns an immutable view of all bits appended so far. Repeated calls
+// without intervening appends return the same view.
+func (g *Growable) Snapshot() *LiveVersion {
+	if s := g.snapshot.Load(); s != nil {
+		return s
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s := g.snapshot.Load(); s != nil {
+		return s
+	}
+	v := g.version()
+	s := &LiveVersion{chunks: slices.Clone(v.chunks), ones: slices.Clone(v.ones), length: v.length}
+	if n := len(s.chunks); n > 0 {
+		// Directory entries are only ever appended, so capped slices of them stay
+		// valid; a partially filled last word is still written by appendBit.
+		open := s.chunks[n-1]
+		words, blocks, supers := len(open.data), len(open.blockRanks), len(open.superBlocks)
+		data := open.data[:words:words]
+		if open.length%64 != 0 {
+			data = slices.Clone(data)
+		}
+		s.chunks[n-1] = newSuccincter(data, open.blockRanks[:blocks:blocks], open.superBlocks[:supers:supers], open.totalOnes, open.length)
+	}
+	g.snapshot.Store(s)
+	return s
+}
+
+// version returns the Growable's bits as a LiveVersion whose open chunk is still
+// being written. It is only valid while g.mu is held.
+func (g *Growable) version() *LiveVersion {
+	if g.v.ones == nil {
+		g.v.ones = []int{0}
+	}
+	return &g.v
+}
+
+func (g *Growable) appendBit(bit bool) {
+	v := g.version()
+	if v.length%liveChunkBits == 0 {
+		// The previous chunk, if any, is full and never written again.
+		v.chunks = append(v.chunks, newSuccincter(nil, nil, nil, 0, 0))
+		v.ones = append(v.ones, v.ones[len(v.ones)-1])
+	}
+	open := v.chunks[len(v.chunks)-1]
+	off := open.length % 64
+	if off == 0 {
+		if len(open.data)%blocksPerSuperBlock == 0 {
+			open.superBlocks = append(open.superBlocks, uint64(open.totalOnes))
+		}
+		open.blockRanks = append(open.blockRanks, uint64(open.totalOnes))
+		open.data = append(open.data, 0)
+	}
+	if bit {
+		open.data[len(open.data)-1] |= 1 << uint(off)
+		open.totalOnes++
+		v.ones[len(v.ones)-1]++
+	}
+	open.length++
+	v.length++
+}
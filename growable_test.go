@@ -0,0 +1,139 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestGrowableMatchesFromBools(t *testing.T) {
+	rng := rand.New(rand.NewSource(15))
+	g := NewGrowable()
+	var model []bool
+
+	for batch := 0; batch < 60; batch++ {
+		bits := randomBools(rng, rng.Intn(200))
+		g.Append(bits...)
+		model = append(model, bits...)
+
+		if !reflect.DeepEqual(g.Snapshot().Freeze(), FromBools(model)) {
+			t.Fatalf("batch %d: snapshot differs from FromBools", batch)
+		}
+	}
+}
+
+func TestGrowableAppendFrom(t *testing.T) {
+	var g Growable
+	AppendFrom(&g, []int{1, 2, 3, 4}, func(v int) bool { return v%2 == 0 })
+	AppendFrom(&g, []int{5, 6}, func(v int) bool { return v%2 == 0 })
+
+	if got := g.Len(); got != 6 {
+		t.Errorf("Len() = %d; want 6", got)
+	}
+	if got := g.Rank(6); got != 3 {
+		t.Errorf("Rank(6) = %d; want 3", got)
+	}
+	if got := g.Select(3); got != 5 {
+		t.Errorf("Select(3) = %d; want 5", got)
+	}
+}
+
+func TestGrowableSnapshotsAreStable(t *testing.T) {
+	var g Growable
+	g.Append(true, false, true)
+	old := g.Snapshot()
+	if g.Snapshot() != old {
+		t.Error("Snapshot without appends returned a new Succincter")
+	}
+
+	// Fill the shared partial word and several more words.
+	for i := 0; i < 500; i++ {
+		g.Append(true)
+	}
+
+	if old.Len() != 3 || old.Ones() != 2 || old.Rank(64) != 2 {
+		t.Errorf("old snapshot changed: Len=%d Ones=%d Rank(64)=%d", old.Len(), old.Ones(), old.Rank(64))
+	}
+	if got := g.Snapshot().Ones(); got != 502 {
+		t.Errorf("new snapshot Ones() = %d; want 502", got)
+	}
+}
+
+func TestGrowableAcrossChunks(t *testing.T) {
+	rng := rand.New(rand.NewSource(32))
+	var g Growable
+	var model []bool
+	var snapshots []*LiveVersion
+	for len(model) < 3*liveChunkBits {
+		bits := randomBools(rng, rng.Intn(5000))
+		g.Append(bits...)
+		model = append(model, bits...)
+		snapshots = append(snapshots, g.Snapshot())
+	}
+	checkRankSelect(t, &g, model)
+
+	for _, s := range snapshots {
+		if !reflect.DeepEqual(s.Freeze(), FromBools(model[:s.Len()])) {
+			t.Fatalf("snapshot of %d bits changed after later appends", s.Len())
+		}
+	}
+}
+
+func TestGrowableSnapshotDoesNotPinData(t *testing.T) {
+	var g Growable
+	g.Append(randomBools(rand.New(rand.NewSource(34)), 1100)...)
+	open := g.v.chunks[0]
+	base, words := &open.data[0], cap(open.data)
+
+	// 1100 bits fill 18 of 32 reserved words, leaving room for ~90 batches.
+	for i := 0; i < 2000; i++ {
+		s := g.Snapshot()
+		g.Append(true, false, true, true, false, true, false, false, true, true)
+		if len(open.data) > words {
+			break
+		}
+		if &open.data[0] != base {
+			t.Fatalf("append after snapshot %d reallocated data", i)
+		}
+		if s.Len() != 1100+10*i {
+			t.Fatalf("snapshot Len() = %d; want %d", s.Len(), 1100+10*i)
+		}
+	}
+}
+
+func TestGrowableConcurrentReads(t *testing.T) {
+	var g Growable
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := g.Snapshot()
+				if s.Ones() > 0 && s.Select(s.Ones()) >= s.Len() {
+					t.Error("snapshot Select past its own length")
+					return
+				}
+				s.Rank(s.Len())
+			}
+		}()
+	}
+
+	for i := 0; i < 3000; i++ {
+		g.Append(i%3 == 0, i%7 == 0)
+	}
+	close(done)
+	wg.Wait()
+
+	if got := g.Rank(6000); got != 1000+429 {
+		t.Errorf("Rank(6000) = %d; want %d", got, 1000+429)
+	}
+}
@@ -0,0 +1,135 @@
+package succincter
+
+import "iter"
+
+// Index keeps a slice together with named predicate bitvectors over it, so queries
+// return elements instead of positions and never index the slice with -1.
+//
+// Ranks and pages are 1-indexed, matching Select. Methods panic if name has not been
+// added. An Index is safe for concurrent reads once all Add and Register calls
+// have completed.
+type Index[T any] struct {
+	items      []T
+	bitvectors map[string]*Succincter
+}
+
+// NewIndex returns an Index over items with no predicates. The Index keeps a
+// reference to items; the caller must not modify it afterwards.
+func NewIndex[T any](items []T) *Index[T] {
+	return &Index[T]{items: items, bitvectors: make(map[string]*Succincter)}
+}
+
+// Add builds a bitvector for predicate under name, replacing any existing one.
+func (ix *Index[T]) Add(name string, predicate func(T) bool) {
+	ix.bitvectors[name] = NewSuccincter(ix.items, predicate)
+}
+
+// Register stores a prebuilt bitvector under name, for example a combination such as
+// And(ix.Bitvector("online"), ix.Bitvector("premium")). Panics if bv.Len() differs
+// from the number of items.
+func (ix *Index[T]) Register(name string, bv *Succincter) {
+	if bv.Len() != len(ix.items) {
+		panic("succincter: Index bitvector length does not match items")
+	}
+	ix.bitvectors[name] = bv
+}
+
+// Len returns the number of items.
+func (ix *Index[T]) Len() int {
+	return len(ix.items)
+}
+
+// Items returns the indexed slice.
+func (ix *Index[T]) Items() []T {
+	return ix.items
+}
+
+// Bitvector returns the bitvector registered under name.
+func (ix *Index[T]) Bitvector(name string) *Succincter {
+	bv, ok := ix.bitvectors[name]
+	if !ok {
+		panic("succincter: Index has no predicate named " + name)
+	}
+	return bv
+}
+
+// Count returns the number of items matching name.
+func (ix *Index[T]) Count(name string) int {
+	return ix.Bitvector(name).Ones()
+}
+
+// CountBefore returns the number of items matching name at positions before pos.
+func (ix *Index[T]) CountBefore(name string, pos int) int {
+	return ix.Bitvector(name).Rank(pos)
+}
+
+// Nth returns the k-th (1-indexed) item matching name, and false if there is none.
+func (ix *Index[T]) Nth(name string, k int) (T, bool) {
+	pos := ix.Bitvector(name).Select(k)
+	if pos == -1 {
+		var zero T
+		return zero, false
+	}
+	return ix.items[pos], true
+}
+
+// Page returns the page-th (1-indexed) group of up to size items matching name.
+// Returns nil when the page is past the last match or page or size is not positive.
+func (ix *Index[T]) Page(name string, page, size int) []T {
+	bv := ix.Bitvector(name)
+	if page <= 0 || size <= 0 {
+		return nil
+	}
+	first := (page-1)*size + 1
+	if first > bv.Ones() {
+		return nil
+	}
+	last := min(first+size-1, bv.Ones())
+
+	out := make([]T, 0, last-first+1)
+	pos := bv.Select(first)
+	for k := first; k <= last; k++ {
+		out = append(out, ix.items[pos])
+		pos = bv.NextOne(pos + 1)
+	}
+	return out
+}
+
+// All returns an iterator over the positions and items matching name, in order.
+func (ix *Index[T]) All(name string) iter.Seq2[int, T] {
+	return ix.AllOf(name)
+}
+
+// AllOf returns an iterator over the positions and items matching every one of names.
+// The bitvectors are intersected lazily; nothing is materialized.
+func (ix *Index[T]) AllOf(names ...string) iter.Seq2[int, T] {
+	sets := make([]*Succincter, len(names))
+	for i, name := range names {
+		sets[i] = ix.Bitvector(name)
+	}
+	return func(yield func(int, T) bool) {
+		for pos := range Intersect(sets...) {
+			if !yield(pos, ix.items[pos]) {
+				return
+			}
+		}
+	}
+}
+
+// AnyOf returns an iterator over the positions and items matching at least one of names.
+func (ix *Index[T]) AnyOf(names ...string) iter.Seq2[int, T] {
+	if len(names) == 0 {
+		return func(func(int, T) bool) {}
+	}
+	union := ix.Bitvector(names[0])
+	for _, name := range names[1:] {
+		union = Or(union, ix.Bitvector(name))
+	}
+	return func(yield func(int, T) bool) {
+		for pos := range union.Positions() {
+			if !yield(pos, ix.items[pos]) {
+				return
+			}
+		}
+	}
+}
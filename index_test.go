@@ -0,0 +1,142 @@
+package succincter
+
+import (
+	"slices"
+	"testing"
+)
+
+type testUser struct {
+	ID      int
+	Online  bool
+	Premium bool
+}
+
+func newTestUserIndex() (*Index[testUser], []testUser) {
+	users := make([]testUser, 100)
+	for i := range users {
+		users[i] = testUser{ID: i, Online: i%3 == 0, Premium: i%5 == 0}
+	}
+	ix := NewIndex(users)
+	ix.Add("online", func(u testUser) bool { return u.Online })
+	ix.Add("premium", func(u testUser) bool { return u.Premium })
+	return ix, users
+}
+
+func TestIndexNthAndCount(t *testing.T) {
+	ix, _ := newTestUserIndex()
+
+	if got := ix.Count("online"); got != 34 {
+		t.Errorf("Count(online) = %d; want 34", got)
+	}
+	if got := ix.CountBefore("premium", 21); got != 5 {
+		t.Errorf("CountBefore(premium, 21) = %d; want 5", got)
+	}
+	if u, ok := ix.Nth("premium", 3); !ok || u.ID != 10 {
+		t.Errorf("Nth(premium, 3) = %v, %v; want ID 10, true", u, ok)
+	}
+	if _, ok := ix.Nth("premium", 21); ok {
+		t.Error("Nth(premium, 21) found an item past the last match")
+	}
+	if _, ok := ix.Nth("premium", 0); ok {
+		t.Error("Nth(premium, 0) found an item")
+	}
+}
+
+func TestIndexPage(t *testing.T) {
+	ix, _ := newTestUserIndex()
+
+	ids := func(us []testUser) []int {
+		out := make([]int, len(us))
+		for i, u := range us {
+			out[i] = u.ID
+		}
+		return out
+	}
+
+	tests := []struct {
+		page, size int
+		want       []int
+	}{
+		{1, 3, []int{0, 5, 10}},
+		{2, 3, []int{15, 20, 25}},
+		{7, 3, []int{90, 95}},
+		{8, 3, nil},
+		{0, 3, nil},
+		{1, 0, nil},
+	}
+	for _, tt := range tests {
+		got := ids(ix.Page("premium", tt.page, tt.size))
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Page(premium, %d, %d) = %v; want %v", tt.page, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestIndexIterators(t *testing.T) {
+	ix, users := newTestUserIndex()
+
+	var all, both, either []int
+	for pos, u := range ix.All("online") {
+		if pos != u.ID {
+			t.Fatalf("All yielded position %d with item ID %d", pos, u.ID)
+		}
+		all = append(all, pos)
+	}
+	for pos := range ix.AllOf("online", "premium") {
+		both = append(both, pos)
+	}
+	for pos := range ix.AnyOf("online", "premium") {
+		either = append(either, pos)
+	}
+
+	var wantAll, wantBoth, wantEither []int
+	for _, u := range users {
+		if u.Online {
+			wantAll = append(wantAll, u.ID)
+		}
+		if u.Online && u.Premium {
+			wantBoth = append(wantBoth, u.ID)
+		}
+		if u.Online || u.Premium {
+			wantEither = append(wantEither, u.ID)
+		}
+	}
+	if !slices.Equal(all, wantAll) {
+		t.Errorf("All(online) = %v; want %v", all, wantAll)
+	}
+	if !slices.Equal(both, wantBoth) {
+		t.Errorf("AllOf(online, premium) = %v; want %v", both, wantBoth)
+	}
+	if !slices.Equal(either, wantEither) {
+		t.Errorf("AnyOf(online, premium) = %v; want %v", either, wantEither)
+	}
+}
+
+func TestIndexRegister(t *testing.T) {
+	ix, _ := newTestUserIndex()
+	ix.Register("vip", And(ix.Bitvector("online"), ix.Bitvector("premium")))
+
+	if u, ok := ix.Nth("vip", 2); !ok || u.ID != 15 {
+		t.Errorf("Nth(vip, 2) = %v, %v; want ID 15, true", u, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register with mismatched length did not panic")
+		}
+	}()
+	ix.Register("bad", FromBools(make([]bool, 5)))
+}
+
+func TestIndexUnknownName(t *testing.T) {
+	ix, _ := newTestUserIndex()
+	defer func() {
+		if recover() == nil {
+			t.Error("Count on unknown name did not panic")
+		}
+	}()
+	ix.Count("banned")
+}
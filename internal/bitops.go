@@ -8,6 +8,11 @@ func Popcount(x uint64) int {
 	return bits.OnesCount64(x)
 }
 
+// TrailingZeros returns the number of trailing 0-bits in x; 64 for x == 0.
+func TrailingZeros(x uint64) int {
+	return bits.TrailingZeros64(x)
+}
+
 // SelectInBlock returns the position of the rank-th 1-bit within a 64-bit block.
 // Returns -1 if the block has fewer than rank 1-bits.
 func SelectInBlock(block uint64, rank int) int {
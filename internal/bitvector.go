@@ -1,5 +1,7 @@
 package internal
 
+import "sync"
+
 func CompressToBitVector[T any](input []T, predicate func(T) bool) []uint64 {
 	result := make([]uint64, (len(input)+63)/64)
 	for i, val := range input {
@@ -9,3 +11,51 @@ func CompressToBitVector[T any](input []T, predicate func(T) bool) []uint64 {
 	}
 	return result
 }
+
+// CompressToBitVectorParallel is CompressToBitVector with predicate evaluation
+// spread across workers goroutines. Each worker owns a contiguous run of whole
+// words, so no two workers ever write the same word.
+func CompressToBitVectorParallel[T any](input []T, predicate func(T) bool, workers int) []uint64 {
+	result := make([]uint64, (len(input)+63)/64)
+	ParallelRanges(len(result), workers, func(lo, hi int) {
+		end := hi * 64
+		if end > len(input) {
+			end = len(input)
+		}
+		for i := lo * 64; i < end; i++ {
+			if predicate(input[i]) {
+				result[i/64] |= 1 << uint(i%64)
+			}
+		}
+	})
+	return result
+}
+
+// ParallelRanges splits [0, n) into at most workers contiguous ranges and calls
+// fn for each range on its own goroutine, returning once all calls finish.
+func ParallelRanges(n, workers int, fn func(lo, hi int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n > 0 {
+			fn(0, n)
+		}
+		return
+	}
+
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,72 @@
+package internal
+
+// PackedInts stores n unsigned integers of a fixed bit width back to back in
+// 64-bit words, so a value may straddle two words.
+type PackedInts struct {
+	words []uint64
+	width int
+	n     int
+}
+
+// NewPackedInts returns n zero values of width bits each. Width must be in [0, 64].
+func NewPackedInts(n, width int) *PackedInts {
+	if width < 0 || width > 64 {
+		panic("internal: PackedInts width out of range [0, 64]")
+	}
+	return &PackedInts{
+		words: make([]uint64, (n*width+63)/64),
+		width: width,
+		n:     n,
+	}
+}
+
+// Len returns the number of values.
+func (p *PackedInts) Len() int {
+	return p.n
+}
+
+// Width returns the number of bits per value.
+func (p *PackedInts) Width() int {
+	return p.width
+}
+
+// Words returns the underlying storage.
+func (p *PackedInts) Words() []uint64 {
+	return p.words
+}
+
+// Get returns value i.
+func (p *PackedInts) Get(i int) uint64 {
+	if p.width == 0 {
+		return 0
+	}
+	bit := i * p.width
+	w, off := bit/64, uint(bit%64)
+	v := p.words[w] >> off
+	if int(off)+p.width > 64 {
+		v |= p.words[w+1] << (64 - off)
+	}
+	return v & p.mask()
+}
+
+// Set stores the low width bits of v as value i.
+func (p *PackedInts) Set(i int, v uint64) {
+	if p.width == 0 {
+		return
+	}
+	v &= p.mask()
+	bit := i * p.width
+	w, off := bit/64, uint(bit%64)
+	p.words[w] = p.words[w]&^(p.mask()<<off) | v<<off
+	if int(off)+p.width > 64 {
+		spill := 64 - off
+		p.words[w+1] = p.words[w+1]&^(p.mask()>>spill) | v>>spill
+	}
+}
+
+func (p *PackedInts) mask() uint64 {
+	if p.width == 64 {
+		return ^uint64(0)
+	}
+	return (uint64(1) << uint(p.width)) - 1
+}
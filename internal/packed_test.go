@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestPackedIntsRoundTrip checks every width, including values straddling words.
+func TestPackedIntsRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for width := 0; width <= 64; width++ {
+		n := 257
+		p := NewPackedInts(n, width)
+		want := make([]uint64, n)
+		mask := ^uint64(0)
+		if width < 64 {
+			mask = (uint64(1) << uint(width)) - 1
+		}
+
+		for i := range want {
+			want[i] = rng.Uint64() & mask
+			p.Set(i, want[i])
+		}
+		// Overwrite a few to check Set clears old bits.
+		for i := 0; i < n; i += 7 {
+			want[i] = rng.Uint64() & mask
+			p.Set(i, want[i])
+		}
+
+		for i := range want {
+			if got := p.Get(i); got != want[i] {
+				t.Fatalf("width %d: Get(%d) = %#x; want %#x", width, i, got, want[i])
+			}
+		}
+		if p.Len() != n || p.Width() != width {
+			t.Fatalf("width %d: Len=%d Width=%d", width, p.Len(), p.Width())
+		}
+	}
+}
+
+// TestPackedIntsSetMasksInput verifies high bits beyond width are dropped.
+func TestPackedIntsSetMasksInput(t *testing.T) {
+	p := NewPackedInts(3, 4)
+	p.Set(1, 0xFF)
+	if p.Get(0) != 0 || p.Get(1) != 0xF || p.Get(2) != 0 {
+		t.Errorf("got %#x %#x %#x; want 0 0xf 0", p.Get(0), p.Get(1), p.Get(2))
+	}
+}
@@ -0,0 +1,85 @@
+package succincter
+
+import (
+	"iter"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// IntersectCountRange returns the number of positions in [lo, hi) set in both a and b.
+// Only the words overlapping the range are touched and nothing is allocated.
+// Panics if a and b have different lengths.
+func IntersectCountRange(a, b *Succincter, lo, hi int) int {
+	checkSameLength(a, b)
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > a.length {
+		hi = a.length
+	}
+	if lo >= hi {
+		return 0
+	}
+
+	first, last := lo/64, (hi-1)/64
+	count := 0
+	for i := first; i <= last; i++ {
+		w := a.data[i] & b.data[i]
+		if i == first {
+			w &^= (uint64(1) << uint(lo%64)) - 1
+		}
+		if i == last && hi%64 != 0 {
+			w &= (uint64(1) << uint(hi%64)) - 1
+		}
+		count += internal.Popcount(w)
+	}
+	return count
+}
+
+// Intersect returns a lazy iterator over the positions set in every one of sets, in
+// ascending order. Each set leaps to the current candidate with NextOne, so sparse
+// sets skip long runs of zeros instead of scanning them. No sets yields nothing.
+func Intersect(sets ...*Succincter) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(sets) == 0 {
+			return
+		}
+		candidate := 0
+		for {
+			agreed := true
+			for _, s := range sets {
+				pos := s.NextOne(candidate)
+				if pos == -1 {
+					return
+				}
+				if pos != candidate {
+					candidate = pos
+					agreed = false
+					break
+				}
+			}
+			if !agreed {
+				continue
+			}
+			if !yield(candidate) {
+				return
+			}
+			candidate++
+		}
+	}
+}
+
+// IntersectSelect returns the position of the rank-th (1-indexed) position set in every
+// one of sets, or -1 if there are fewer than rank such positions.
+func IntersectSelect(rank int, sets ...*Succincter) int {
+	if rank <= 0 {
+		return -1
+	}
+	for pos := range Intersect(sets...) {
+		rank--
+		if rank == 0 {
+			return pos
+		}
+	}
+	return -1
+}
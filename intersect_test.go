@@ -0,0 +1,100 @@
+package succincter
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNextOne(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	input := make([]bool, 3000)
+	for i := range input {
+		input[i] = rng.Intn(50) == 0
+	}
+	s := FromBools(input)
+
+	for pos := -2; pos <= len(input)+70; pos++ {
+		want := -1
+		for i := max(pos, 0); i < len(input); i++ {
+			if input[i] {
+				want = i
+				break
+			}
+		}
+		if got := s.NextOne(pos); got != want {
+			t.Fatalf("NextOne(%d) = %d; want %d", pos, got, want)
+		}
+	}
+
+	if got := FromBools(nil).NextOne(0); got != -1 {
+		t.Errorf("NextOne(0) on empty = %d; want -1", got)
+	}
+}
+
+func TestIntersectCountRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	x := randomBools(rng, 2000)
+	y := randomBools(rng, 2000)
+	a, b := FromBools(x), FromBools(y)
+
+	ranges := [][2]int{{0, 2000}, {0, 0}, {5, 6}, {63, 65}, {100, 1500}, {1999, 2000}, {-10, 3000}, {70, 10}}
+	for _, r := range ranges {
+		want := 0
+		for i := max(r[0], 0); i < min(r[1], len(x)); i++ {
+			if x[i] && y[i] {
+				want++
+			}
+		}
+		if got := IntersectCountRange(a, b, r[0], r[1]); got != want {
+			t.Errorf("IntersectCountRange(%d, %d) = %d; want %d", r[0], r[1], got, want)
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	inputs := [][]bool{randomBools(rng, 4000), randomBools(rng, 4000), randomBools(rng, 3500)}
+	sets := make([]*Succincter, len(inputs))
+	for i, in := range inputs {
+		sets[i] = FromBools(in)
+	}
+
+	var want []int
+	for pos := 0; pos < 3500; pos++ {
+		if inputs[0][pos] && inputs[1][pos] && inputs[2][pos] {
+			want = append(want, pos)
+		}
+	}
+
+	got := slices.Collect(Intersect(sets...))
+	if !slices.Equal(got, want) {
+		t.Fatalf("Intersect = %v; want %v", got, want)
+	}
+
+	for k := 1; k <= len(want); k++ {
+		if pos := IntersectSelect(k, sets...); pos != want[k-1] {
+			t.Errorf("IntersectSelect(%d) = %d; want %d", k, pos, want[k-1])
+		}
+	}
+	if pos := IntersectSelect(len(want)+1, sets...); pos != -1 {
+		t.Errorf("IntersectSelect past end = %d; want -1", pos)
+	}
+	if got := slices.Collect(Intersect()); len(got) != 0 {
+		t.Errorf("Intersect() = %v; want empty", got)
+	}
+}
+
+func TestIntersectEarlyStop(t *testing.T) {
+	s := FromBools([]bool{true, true, true, true})
+	count := 0
+	for range Intersect(s, s) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("iterated %d times; want 2", count)
+	}
+}
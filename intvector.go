@@ -0,0 +1,57 @@
+package succincter
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// IntVector stores unsigned integers using the same number of bits for each, just
+// enough for the largest value, packed back to back in 64-bit words.
+type IntVector struct {
+	packed *internal.PackedInts
+}
+
+// NewIntVector packs values, using bits.Len64 of the largest as the width.
+func NewIntVector(values []uint64) *IntVector {
+	width := 0
+	if len(values) > 0 {
+		width = bits.Len64(slices.Max(values))
+	}
+	v := &IntVector{packed: internal.NewPackedInts(len(values), width)}
+	for i, x := range values {
+		v.packed.Set(i, x)
+	}
+	return v
+}
+
+// Len returns the number of values.
+func (v *IntVector) Len() int {
+	return v.packed.Len()
+}
+
+// Width returns the number of bits per value.
+func (v *IntVector) Width() int {
+	return v.packed.Width()
+}
+
+// Get returns value i. Panics if i is outside [0, Len()).
+func (v *IntVector) Get(i int) uint64 {
+	if i < 0 || i >= v.packed.Len() {
+		panic("succincter: IntVector index out of range")
+	}
+	return v.packed.Get(i)
+}
+
+// Set replaces value i. Panics if i is outside [0, Len()) or x does not fit in
+// Width bits.
+func (v *IntVector) Set(i int, x uint64) {
+	if i < 0 || i >= v.packed.Len() {
+		panic("succincter: IntVector index out of range")
+	}
+	if bits.Len64(x) > v.packed.Width() {
+		panic("succincter: IntVector value wider than its width")
+	}
+	v.packed.Set(i, x)
+}
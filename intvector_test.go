@@ -0,0 +1,56 @@
+package succincter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIntVector(t *testing.T) {
+	rng := rand.New(rand.NewSource(47))
+	for _, maxValue := range []uint64{0, 1, 1000, 1<<40 + 3, ^uint64(0)} {
+		values := make([]uint64, 500)
+		for i := range values {
+			values[i] = rng.Uint64() % (maxValue/2 + 1)
+		}
+		values[rng.Intn(len(values))] = maxValue
+		v := NewIntVector(values)
+		if v.Len() != len(values) {
+			t.Fatalf("Len() = %d; want %d", v.Len(), len(values))
+		}
+		for i, want := range values {
+			if got := v.Get(i); got != want {
+				t.Fatalf("max %d: Get(%d) = %d; want %d", maxValue, i, got, want)
+			}
+		}
+		v.Set(7, maxValue)
+		if v.Get(7) != maxValue || v.Get(6) != values[6] || v.Get(8) != values[8] {
+			t.Fatalf("max %d: Set(7) disturbed neighbors or did not stick", maxValue)
+		}
+	}
+	if w := NewIntVector([]uint64{5, 8, 3}).Width(); w != 4 {
+		t.Errorf("Width() = %d; want 4", w)
+	}
+}
+
+func TestIntVectorPanics(t *testing.T) {
+	v := NewIntVector([]uint64{5, 8, 3})
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"Get negative", func() { v.Get(-1) }},
+		{"Get at end", func() { v.Get(3) }},
+		{"Set at end", func() { v.Set(3, 1) }},
+		{"Set too wide", func() { v.Set(0, 16) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package succincter
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// InvertedIndex maps terms to the positions of the items containing them, for term
+// search over free text. A tokenizer supplies each item's terms; matching is exact,
+// so normalize case in the tokenizer if needed.
+//
+// Each posting list is stored in whichever form is smaller: an Elias-Fano sequence
+// for rare terms, or a Succincter bitvector for common ones. Both expose NextOne, so
+// AND queries leapfrog between lists, skipping directly to the next candidate instead
+// of merging every posting.
+type InvertedIndex[T any] struct {
+	items    []T
+	postings map[string]postingList
+}
+
+// postingList is the part of the Succincter API that queries need; sparsePostings
+// provides the same over Elias-Fano.
+type postingList interface {
+	Ones() int
+	NextOne(pos int) int
+}
+
+// sparsePostings is a posting list stored as an Elias-Fano sequence.
+type sparsePostings struct {
+	ef *EFSequence
+}
+
+func (p sparsePostings) Ones() int {
+	return p.ef.Len()
+}
+
+func (p sparsePostings) NextOne(pos int) int {
+	i, v := p.ef.NextGEQ(uint64(max(pos, 0)))
+	if i < 0 {
+		return -1
+	}
+	return int(v)
+}
+
+// NewInvertedIndex indexes items by the terms tokenize returns for each. Repeated
+// terms within one item are indexed once.
+func NewInvertedIndex[T any](items []T, tokenize func(T) []string) *InvertedIndex[T] {
+	lists := make(map[string][]int)
+	for pos, item := range items {
+		for _, term := range tokenize(item) {
+			list := lists[term]
+			if len(list) == 0 || list[len(list)-1] != pos {
+				lists[term] = append(list, pos)
+			}
+		}
+	}
+
+	ix := &InvertedIndex[T]{items: items, postings: make(map[string]postingList, len(lists))}
+	n := len(items)
+	for term, list := range lists {
+		// Elias-Fano takes about 2 + log2(n/m) bits per posting; use a bitvector
+		// once that exceeds one bit per item.
+		m := len(list)
+		if m*(2+bits.Len(uint(n/m))) >= n {
+			ix.postings[term] = FromPositions(list, n)
+			continue
+		}
+		values := make([]uint64, m)
+		for i, pos := range list {
+			values[i] = uint64(pos)
+		}
+		ix.postings[term] = sparsePostings{ef: NewEFSequence(values)}
+	}
+	return ix
+}
+
+// Len returns the number of indexed items.
+func (ix *InvertedIndex[T]) Len() int {
+	return len(ix.items)
+}
+
+// Items returns the indexed slice.
+func (ix *InvertedIndex[T]) Items() []T {
+	return ix.items
+}
+
+// Terms returns the number of distinct terms.
+func (ix *InvertedIndex[T]) Terms() int {
+	return len(ix.postings)
+}
+
+// Count returns the number of items containing term.
+func (ix *InvertedIndex[T]) Count(term string) int {
+	if list, ok := ix.postings[term]; ok {
+		return list.Ones()
+	}
+	return 0
+}
+
+// Postings iterates the positions of items containing term in ascending order.
+func (ix *InvertedIndex[T]) Postings(term string) iter.Seq[int] {
+	return ix.iterate(ix.termCursor(term))
+}
+
+// And iterates the positions of items containing every term, in ascending order.
+// Lists are visited rarest first, and each skips straight to the current candidate.
+func (ix *InvertedIndex[T]) And(terms ...string) iter.Seq[int] {
+	cursors := make([]cursor, len(terms))
+	for i, term := range terms {
+		cursors[i] = ix.termCursor(term)
+	}
+	return ix.iterate(ix.andCursor(cursors))
+}
+
+// Or iterates the positions of items containing any term, in ascending order.
+func (ix *InvertedIndex[T]) Or(terms ...string) iter.Seq[int] {
+	cursors := make([]cursor, len(terms))
+	for i, term := range terms {
+		cursors[i] = ix.termCursor(term)
+	}
+	return ix.iterate(orCursor(cursors, len(ix.items)))
+}
+
+// Query parses expr with the BitmapIndex query syntax (AND, OR, NOT, parentheses;
+// also &&, || and !) over terms, and iterates matching positions in ascending order.
+// Unknown terms match nothing.
+func (ix *InvertedIndex[T]) Query(expr string) (iter.Seq[int], error) {
+	node, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	return ix.iterate(ix.nodeCursor(node)), nil
+}
+
+// cursor evaluates a query lazily: next returns the first match at or after pos, or
+// Len() if there is none. estimate bounds the number of matches.
+type cursor struct {
+	next     func(pos int) int
+	estimate int
+}
+
+func (ix *InvertedIndex[T]) iterate(c cursor) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for pos := c.next(0); pos < len(ix.items); pos = c.next(pos + 1) {
+			if !yield(pos) {
+				return
+			}
+		}
+	}
+}
+
+func (ix *InvertedIndex[T]) termCursor(term string) cursor {
+	n := len(ix.items)
+	list, ok := ix.postings[term]
+	if !ok {
+		return cursor{next: func(int) int { return n }}
+	}
+	return cursor{
+		next: func(pos int) int {
+			if next := list.NextOne(pos); next >= 0 {
+				return next
+			}
+			return n
+		},
+		estimate: list.Ones(),
+	}
+}
+
+func (ix *InvertedIndex[T]) nodeCursor(node queryNode) cursor {
+	n := len(ix.items)
+	switch q := node.(type) {
+	case queryName:
+		return ix.termCursor(q.name)
+	case queryNot:
+		operand := ix.nodeCursor(q.operand)
+		return cursor{
+			next: func(pos int) int {
+				for ; pos < n && operand.next(pos) == pos; pos++ {
+				}
+				return pos
+			},
+			estimate: n - operand.estimate,
+		}
+	case queryAnd:
+		cursors := make([]cursor, len(q.operands))
+		for i, op := range q.operands {
+			cursors[i] = ix.nodeCursor(op)
+		}
+		return ix.andCursor(cursors)
+	case queryOr:
+		cursors := make([]cursor, len(q.operands))
+		for i, op := range q.operands {
+			cursors[i] = ix.nodeCursor(op)
+		}
+		return orCursor(cursors, n)
+	}
+	panic("succincter: unknown query node")
+}
+
+// andCursor leapfrogs: each cursor in turn jumps to the current candidate, and a
+// candidate every cursor lands on unmoved is a match.
+func (ix *InvertedIndex[T]) andCursor(cursors []cursor) cursor {
+	n := len(ix.items)
+	if len(cursors) == 0 {
+		return cursor{next: func(int) int { return n }}
+	}
+	slices.SortFunc(cursors, func(a, b cursor) int { return cmp.Compare(a.estimate, b.estimate) })
+	return cursor{
+		next: func(pos int) int {
+			candidate := cursors[0].next(pos)
+			for i, agreed := 1%len(cursors), 1; agreed < len(cursors) && candidate < n; i = (i + 1) % len(cursors) {
+				if next := cursors[i].next(candidate); next == candidate {
+					agreed++
+				} else {
+					candidate, agreed = next, 1
+				}
+			}
+			return candidate
+		},
+		estimate: cursors[0].estimate,
+	}
+}
+
+// orCursor returns the smallest next match among cursors.
+func orCursor(cursors []cursor, n int) cursor {
+	estimate := 0
+	for _, c := range cursors {
+		estimate += c.estimate
+	}
+	return cursor{
+		next: func(pos int) int {
+			best := n
+			for _, c := range cursors {
+				best = min(best, c.next(pos))
+			}
+			return best
+		},
+		estimate: min(estimate, n),
+	}
+}
@@ -0,0 +1,111 @@
+package succincter
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInvertedIndex(t *testing.T) {
+	rng := rand.New(rand.NewSource(48))
+	// Term frequencies from very rare to nearly every item exercise both encodings.
+	vocabulary := []string{"error", "timeout", "db", "disk", "user", "login", "rare", "the"}
+	weights := []float64{0.3, 0.05, 0.1, 0.02, 0.5, 0.2, 0.001, 0.95}
+	docs := make([][]string, 5000)
+	for i := range docs {
+		for j, term := range vocabulary {
+			if rng.Float64() < weights[j] {
+				docs[i] = append(docs[i], term, term)
+			}
+		}
+	}
+	ix := NewInvertedIndex(docs, func(d []string) []string { return d })
+
+	match := func(pred func(doc []string) bool) []int {
+		var out []int
+		for pos, d := range docs {
+			if pred(d) {
+				out = append(out, pos)
+			}
+		}
+		return out
+	}
+	has := func(term string) func([]string) bool {
+		return func(d []string) bool { return slices.Contains(d, term) }
+	}
+
+	for _, term := range append(vocabulary, "missing") {
+		want := match(has(term))
+		if got := slices.Collect(ix.Postings(term)); !equalPositions(got, want) {
+			t.Fatalf("Postings(%s) = %d positions, want %d", term, len(got), len(want))
+		}
+		if ix.Count(term) != len(want) {
+			t.Fatalf("Count(%s) = %d, want %d", term, ix.Count(term), len(want))
+		}
+	}
+	if _, ok := ix.postings["rare"].(sparsePostings); !ok {
+		t.Error("rare term not stored as Elias-Fano")
+	}
+	if _, ok := ix.postings["the"].(*Succincter); !ok {
+		t.Error("common term not stored as a bitvector")
+	}
+	if ix.Terms() != len(vocabulary) {
+		t.Errorf("Terms() = %d, want %d", ix.Terms(), len(vocabulary))
+	}
+
+	if got, want := slices.Collect(ix.And("error", "db", "the")), match(func(d []string) bool {
+		return has("error")(d) && has("db")(d) && has("the")(d)
+	}); !equalPositions(got, want) {
+		t.Errorf("And = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(ix.Or("rare", "disk")), match(func(d []string) bool {
+		return has("rare")(d) || has("disk")(d)
+	}); !equalPositions(got, want) {
+		t.Errorf("Or = %v, want %v", got, want)
+	}
+	if got := slices.Collect(ix.And("error", "missing")); len(got) != 0 {
+		t.Errorf("And with missing term = %v, want none", got)
+	}
+
+	queries := []struct {
+		expr string
+		pred func([]string) bool
+	}{
+		{"error AND (timeout OR disk)", func(d []string) bool { return has("error")(d) && (has("timeout")(d) || has("disk")(d)) }},
+		{"user && !login && !the", func(d []string) bool { return has("user")(d) && !has("login")(d) && !has("the")(d) }},
+		{"NOT the", func(d []string) bool { return !has("the")(d) }},
+		{"rare OR missing", has("rare")},
+	}
+	for _, q := range queries {
+		seq, err := ix.Query(q.expr)
+		if err != nil {
+			t.Fatalf("Query(%q): %v", q.expr, err)
+		}
+		if got, want := slices.Collect(seq), match(q.pred); !equalPositions(got, want) {
+			t.Errorf("Query(%q) = %d positions, want %d", q.expr, len(got), len(want))
+		}
+	}
+	if _, err := ix.Query("error AND"); err == nil {
+		t.Error("Query with dangling AND returned no error")
+	}
+}
+
+func equalPositions(a, b []int) bool {
+	return len(a) == len(b) && (len(a) == 0 || slices.Equal(a, b))
+}
+
+func TestInvertedIndexEarlyStop(t *testing.T) {
+	lines := []string{"a b", "a", "a b", "b", "a b"}
+	ix := NewInvertedIndex(lines, strings.Fields)
+	var got []int
+	for pos := range ix.And("a", "b") {
+		got = append(got, pos)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("first two of And(a, b) = %v; want [0 2]", got)
+	}
+}
@@ -0,0 +1,231 @@
+package succincter
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// K2Tree is a succinct adjacency matrix for sparse directed graphs on n nodes. The
+// matrix is padded to a power-of-two side and split recursively into 2x2 quadrants;
+// each level stores one bit per quadrant of every non-empty node, in level order, so
+// empty regions cost nothing below their parent bit. The children of the internal
+// 1-bit at position x start at 4*Rank(x+1).
+//
+// All levels are kept in one Succincter; bits from leafStart onward are the last
+// level, whose 1-bits are edges.
+type K2Tree struct {
+	bits      *Succincter
+	leafStart int
+	height    int
+	n         int
+	edges     int
+}
+
+// NewK2Tree builds a k²-tree over nodes [0, n) from an edge list of (from, to)
+// pairs. Duplicate edges are stored once. Panics if an endpoint is outside [0, n) or
+// n exceeds 1<<32.
+func NewK2Tree(n int, edges [][2]int) *K2Tree {
+	if n < 0 || uint64(n) > 1<<32 {
+		panic("succincter: K2Tree node count out of range")
+	}
+	height := max(bits.Len(uint(max(n, 1)-1)), 1)
+
+	// Sorting Morton codes (row and column bits interleaved, row first) orders
+	// every level's nodes as a level-order traversal visits them.
+	codes := make([]uint64, 0, len(edges))
+	for _, e := range edges {
+		if e[0] < 0 || e[0] >= n || e[1] < 0 || e[1] >= n {
+			panic("succincter: K2Tree edge endpoint out of range")
+		}
+		codes = append(codes, mortonCode(uint64(e[0]), uint64(e[1]), height))
+	}
+	slices.Sort(codes)
+	codes = slices.Compact(codes)
+
+	b := NewBuilder(0)
+	leafStart := 0
+	for level := 0; level < height; level++ {
+		if level == height-1 {
+			leafStart = b.Len()
+		}
+		shift := uint(2 * (height - 1 - level))
+		// Each distinct prefix at this level is a non-empty node; emit a mask of
+		// which of its four children are non-empty.
+		for i := 0; i < len(codes); {
+			parent := codes[i] >> (shift + 2)
+			var mask uint64
+			for ; i < len(codes) && codes[i]>>(shift+2) == parent; i++ {
+				mask |= 1 << (codes[i] >> shift & 3)
+			}
+			b.AppendWord(mask, 4)
+		}
+	}
+	return &K2Tree{bits: b.Build(), leafStart: leafStart, height: height, n: n, edges: len(codes)}
+}
+
+// Len returns the number of nodes.
+func (t *K2Tree) Len() int {
+	return t.n
+}
+
+// Edges returns the number of distinct edges.
+func (t *K2Tree) Edges() int {
+	return t.edges
+}
+
+// HasEdge reports whether the edge (u, v) exists. O(log n) time.
+func (t *K2Tree) HasEdge(u, v int) bool {
+	if u < 0 || u >= t.n || v < 0 || v >= t.n || t.edges == 0 {
+		return false
+	}
+	base := 0
+	for level := 0; level < t.height; level++ {
+		shift := uint(t.height - 1 - level)
+		x := base + int(uint(u)>>shift&1)<<1 + int(uint(v)>>shift&1)
+		if !t.bit(x) {
+			return false
+		}
+		base = 4 * t.bits.Rank(x+1)
+	}
+	return true
+}
+
+// Neighbors iterates the targets of edges leaving u in ascending order.
+func (t *K2Tree) Neighbors(u int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, v := range t.Range(u, u+1, 0, t.n) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ReverseNeighbors iterates the sources of edges entering v in ascending order.
+func (t *K2Tree) ReverseNeighbors(v int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for u := range t.Range(0, t.n, v, v+1) {
+			if !yield(u) {
+				return
+			}
+		}
+	}
+}
+
+// Range iterates the edges (u, v) with rowLo <= u < rowHi and colLo <= v < colHi.
+// Quadrants that miss the rectangle are skipped whole, so edges are yielded in
+// Z-order: ascending by v within a single row, ascending by u within a single column.
+func (t *K2Tree) Range(rowLo, rowHi, colLo, colHi int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		rowLo, colLo = max(rowLo, 0), max(colLo, 0)
+		rowHi, colHi = min(rowHi, t.n), min(colHi, t.n)
+		if rowLo >= rowHi || colLo >= colHi || t.edges == 0 {
+			return
+		}
+		t.rangeFrom(0, 0, 0, 1<<uint(t.height), rowLo, rowHi, colLo, colHi, yield)
+	}
+}
+
+// rangeFrom visits the four children, starting at base, of the node covering the
+// size x size submatrix at (row, col). It returns false once yield does.
+func (t *K2Tree) rangeFrom(base, row, col, size, rowLo, rowHi, colLo, colHi int, yield func(int, int) bool) bool {
+	half := size / 2
+	for q := 0; q < 4; q++ {
+		r, c := row+q>>1*half, col+q&1*half
+		if r >= rowHi || r+half <= rowLo || c >= colHi || c+half <= colLo || !t.bit(base+q) {
+			continue
+		}
+		if base+q >= t.leafStart {
+			if !yield(r, c) {
+				return false
+			}
+			continue
+		}
+		if !t.rangeFrom(4*t.bits.Rank(base+q+1), r, c, half, rowLo, rowHi, colLo, colHi, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the tree in the same format family as Succincter.
+func (t *K2Tree) MarshalBinary() ([]byte, error) {
+	buf := appendHeader(nil, kindK2Tree)
+	buf = appendUint64(buf, uint64(t.n))
+	buf = appendUint64(buf, uint64(t.edges))
+	buf = appendUint64(buf, uint64(t.leafStart))
+	return appendBitvector(buf, t.bits), nil
+}
+
+// UnmarshalBinary replaces t with a tree encoded by MarshalBinary.
+func (t *K2Tree) UnmarshalBinary(data []byte) error {
+	d, err := newDecoder(data, kindK2Tree)
+	if err != nil {
+		return err
+	}
+	n, err := d.int(min(1<<32, math.MaxInt))
+	if err != nil {
+		return err
+	}
+	// n*n overflows uint64 at n == 1<<32, where any edge count fits.
+	maxEdges := uint64(math.MaxUint64)
+	if uint64(n) < 1<<32 {
+		maxEdges = uint64(n) * uint64(n)
+	}
+	edges, err := d.int(maxEdges)
+	if err != nil {
+		return err
+	}
+	leafStart, err := d.int(uint64(len(data)) * 8)
+	if err != nil {
+		return err
+	}
+	bv, err := d.bitvector()
+	if err != nil {
+		return err
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	height := max(bits.Len(uint(max(n, 1)-1)), 1)
+	if !validK2TreeLevels(bv, leafStart, height) || bv.Ones()-bv.Rank(leafStart) != edges {
+		return errors.New("succincter: malformed K2Tree encoding")
+	}
+	*t = K2Tree{bits: bv, leafStart: leafStart, height: height, n: n, edges: edges}
+	return nil
+}
+
+// validK2TreeLevels reports whether bv splits into exactly height levels, the last
+// starting at leafStart. The root level is one 4-bit block and every 1-bit of a
+// level owns one 4-bit block of the next, so the boundaries follow from the counts;
+// queries then never step past the end of bv.
+func validK2TreeLevels(bv *Succincter, leafStart, height int) bool {
+	if bv.Len() == 0 {
+		return leafStart == 0
+	}
+	start, end := 0, 4
+	for level := 1; level < height; level++ {
+		next := end + 4*(bv.Rank(end)-bv.Rank(start))
+		if end > bv.Len() || next > bv.Len() {
+			return false
+		}
+		start, end = end, next
+	}
+	return start == leafStart && end == bv.Len()
+}
+
+func (t *K2Tree) bit(pos int) bool {
+	return t.bits.data[pos/64]>>uint(pos%64)&1 == 1
+}
+
+// mortonCode interleaves the low height bits of u and v, u's bit first at each level.
+func mortonCode(u, v uint64, height int) uint64 {
+	var code uint64
+	for i := height - 1; i >= 0; i-- {
+		code = code<<2 | (u>>uint(i)&1)<<1 | v>>uint(i)&1
+	}
+	return code
+}
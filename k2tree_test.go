@@ -0,0 +1,180 @@
+package succincter
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestK2Tree(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	for _, n := range []int{0, 1, 2, 3, 17, 64, 300} {
+		for _, m := range []int{0, 1, n, 5 * n} {
+			if n == 0 && m > 0 {
+				continue
+			}
+			matrix := make([][]bool, n)
+			for i := range matrix {
+				matrix[i] = make([]bool, n)
+			}
+			var edges [][2]int
+			for i := 0; i < m; i++ {
+				u, v := rng.Intn(n), rng.Intn(n)
+				edges = append(edges, [2]int{u, v})
+				matrix[u][v] = true
+			}
+			tree := NewK2Tree(n, edges)
+			checkK2Tree(t, tree, matrix)
+		}
+	}
+}
+
+func checkK2Tree(t *testing.T, tree *K2Tree, matrix [][]bool) {
+	t.Helper()
+	n := len(matrix)
+	total := 0
+	for u := 0; u < n; u++ {
+		var out, in []int
+		for v := 0; v < n; v++ {
+			if matrix[u][v] {
+				out = append(out, v)
+				total++
+			}
+			if matrix[v][u] {
+				in = append(in, v)
+			}
+			if tree.HasEdge(u, v) != matrix[u][v] {
+				t.Fatalf("n=%d: HasEdge(%d, %d) = %v", n, u, v, !matrix[u][v])
+			}
+		}
+		if got := slices.Collect(tree.Neighbors(u)); !slices.Equal(got, out) {
+			t.Fatalf("n=%d: Neighbors(%d) = %v, want %v", n, u, got, out)
+		}
+		if got := slices.Collect(tree.ReverseNeighbors(u)); !slices.Equal(got, in) {
+			t.Fatalf("n=%d: ReverseNeighbors(%d) = %v, want %v", n, u, got, in)
+		}
+	}
+	if tree.Len() != n || tree.Edges() != total {
+		t.Fatalf("Len, Edges = %d, %d; want %d, %d", tree.Len(), tree.Edges(), n, total)
+	}
+}
+
+func TestK2TreeRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	n := 100
+	var edges [][2]int
+	set := make(map[[2]int]bool)
+	for i := 0; i < 800; i++ {
+		e := [2]int{rng.Intn(n), rng.Intn(n)}
+		edges = append(edges, e)
+		set[e] = true
+	}
+	tree := NewK2Tree(n, edges)
+	for i := 0; i < 50; i++ {
+		r1, r2 := rng.Intn(n+10)-5, rng.Intn(n+10)-5
+		c1, c2 := rng.Intn(n+10)-5, rng.Intn(n+10)-5
+		want := 0
+		for e := range set {
+			if e[0] >= r1 && e[0] < r2 && e[1] >= c1 && e[1] < c2 {
+				want++
+			}
+		}
+		got := 0
+		for u, v := range tree.Range(r1, r2, c1, c2) {
+			if u < r1 || u >= r2 || v < c1 || v >= c2 || !set[[2]int{u, v}] {
+				t.Fatalf("Range(%d, %d, %d, %d) yielded (%d, %d)", r1, r2, c1, c2, u, v)
+			}
+			got++
+		}
+		if got != want {
+			t.Fatalf("Range(%d, %d, %d, %d) yielded %d edges, want %d", r1, r2, c1, c2, got, want)
+		}
+	}
+	if tree.HasEdge(-1, 0) || tree.HasEdge(0, n) {
+		t.Error("HasEdge out of range = true")
+	}
+}
+
+func TestK2TreeInvalidEdge(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewK2Tree with out-of-range endpoint did not panic")
+		}
+	}()
+	NewK2Tree(3, [][2]int{{0, 3}})
+}
+
+func TestK2TreeMarshalBinary(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	for _, n := range []int{0, 5, 200} {
+		var edges [][2]int
+		for i := 0; i < 3*n; i++ {
+			edges = append(edges, [2]int{rng.Intn(n), rng.Intn(n)})
+		}
+		tree := NewK2Tree(n, edges)
+		data, err := tree.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		var decoded K2Tree
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		matrix := make([][]bool, n)
+		for u := range matrix {
+			matrix[u] = make([]bool, n)
+			for v := range matrix[u] {
+				matrix[u][v] = tree.HasEdge(u, v)
+			}
+		}
+		checkK2Tree(t, &decoded, matrix)
+
+		if err := decoded.UnmarshalBinary(data[:len(data)-1]); n > 0 && err == nil {
+			t.Error("UnmarshalBinary of truncated data returned no error")
+		}
+	}
+}
+
+func TestK2TreeMarshalBinaryMaxNodes(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("1<<32 nodes needs a 64-bit int")
+	}
+	var wide int64 = 1 << 32
+	n := int(wide)
+	tree := NewK2Tree(n, [][2]int{{1, 2}, {n - 1, 0}})
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var decoded K2Tree
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if decoded.Len() != n || decoded.Edges() != 2 {
+		t.Errorf("decoded Len=%d Edges=%d; want %d 2", decoded.Len(), decoded.Edges(), n)
+	}
+	if !decoded.HasEdge(1, 2) || !decoded.HasEdge(n-1, 0) || decoded.HasEdge(2, 1) {
+		t.Error("decoded tree has the wrong edges")
+	}
+}
+
+func TestK2TreeUnmarshalBinaryRejectsWrongHeight(t *testing.T) {
+	var edges [][2]int
+	for u := 0; u < 4; u++ {
+		for v := 0; v < 4; v++ {
+			edges = append(edges, [2]int{u, v})
+		}
+	}
+	data, err := NewK2Tree(4, edges).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	// A 16-node tree needs four levels; the encoded bits only have two.
+	binary.LittleEndian.PutUint64(data[len(appendHeader(nil, kindK2Tree)):], 16)
+	var decoded K2Tree
+	if err := decoded.UnmarshalBinary(data); err == nil {
+		t.Error("UnmarshalBinary accepted levels that do not match n")
+	}
+}
@@ -0,0 +1,189 @@
+package succincter
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"slices"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// KmerIndex counts and locates every k-mer (length-k substring) of a nucleotide
+// sequence. Each k-mer is 2-bit packed into a uint64 (A=0, C=1, G=2, T=3, so numeric
+// order is lexicographic order); the distinct k-mers form a sorted dictionary stored
+// as an Elias-Fano sequence. A k-mer's rank in that dictionary indexes a second
+// Elias-Fano sequence of cumulative counts, which delimits its occurrence positions
+// in a packed array.
+//
+// Bases other than A, C, G and T (in either case), such as N, break k-mers: no
+// k-mer spanning one is indexed. With canonical set, a k-mer and its reverse
+// complement are counted together under the smaller encoding.
+type KmerIndex struct {
+	k         int
+	canonical bool
+	length    int
+	kmers     *EFSequence // distinct encoded k-mers, ascending
+	offsets   *EFSequence // offsets[r]: occurrences of k-mers before rank r
+	positions *internal.PackedInts
+}
+
+// NewKmerIndex indexes all k-mers of seq. Panics if k is outside [1, 32].
+func NewKmerIndex[T ~byte](seq []T, k int, canonical bool) *KmerIndex {
+	if k < 1 || k > 32 {
+		panic("succincter: k-mer length must be between 1 and 32")
+	}
+	type occurrence struct {
+		kmer uint64
+		pos  int
+	}
+	mask := ^uint64(0) >> uint(64-2*k)
+	var occurrences []occurrence
+	var fwd, rev uint64
+	valid := 0
+	for i, b := range seq {
+		c, ok := nucleotideCode(byte(b))
+		if !ok {
+			valid = 0
+			continue
+		}
+		fwd = (fwd<<2 | c) & mask
+		rev = rev>>2 | (3-c)<<uint(2*k-2)
+		if valid++; valid < k {
+			continue
+		}
+		kmer := fwd
+		if canonical {
+			kmer = min(fwd, rev)
+		}
+		occurrences = append(occurrences, occurrence{kmer, i - k + 1})
+	}
+	// Positions were generated in ascending order, so a stable sort keeps each
+	// k-mer's position list sorted.
+	slices.SortStableFunc(occurrences, func(a, b occurrence) int { return cmp.Compare(a.kmer, b.kmer) })
+
+	kx := &KmerIndex{
+		k:         k,
+		canonical: canonical,
+		length:    len(seq),
+		positions: internal.NewPackedInts(len(occurrences), bits.Len(uint(len(seq)))),
+	}
+	var distinct []uint64
+	var offsets []uint64
+	for j, o := range occurrences {
+		if j == 0 || o.kmer != occurrences[j-1].kmer {
+			distinct = append(distinct, o.kmer)
+			offsets = append(offsets, uint64(j))
+		}
+		kx.positions.Set(j, uint64(o.pos))
+	}
+	kx.kmers = NewEFSequence(distinct)
+	kx.offsets = NewEFSequence(append(offsets, uint64(len(occurrences))))
+	return kx
+}
+
+// K returns the k-mer length.
+func (kx *KmerIndex) K() int {
+	return kx.k
+}
+
+// Len returns the length of the indexed sequence.
+func (kx *KmerIndex) Len() int {
+	return kx.length
+}
+
+// Distinct returns the number of distinct k-mers.
+func (kx *KmerIndex) Distinct() int {
+	return kx.kmers.Len()
+}
+
+// Total returns the number of indexed k-mer occurrences.
+func (kx *KmerIndex) Total() int {
+	return kx.positions.Len()
+}
+
+// Count returns the number of occurrences of kmer, or 0 if it does not occur or is
+// not a valid k-mer of length K.
+func (kx *KmerIndex) Count(kmer string) int {
+	r, ok := kx.rank(kmer)
+	if !ok {
+		return 0
+	}
+	return int(kx.offsets.Access(r+1) - kx.offsets.Access(r))
+}
+
+// Positions iterates the start positions of kmer in ascending order. With a canonical
+// index, occurrences of its reverse complement are included.
+func (kx *KmerIndex) Positions(kmer string) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		r, ok := kx.rank(kmer)
+		if !ok {
+			return
+		}
+		lo, hi := int(kx.offsets.Access(r)), int(kx.offsets.Access(r+1))
+		for j := lo; j < hi; j++ {
+			if !yield(int(kx.positions.Get(j))) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates the distinct k-mers in lexicographic order with their counts.
+func (kx *KmerIndex) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		prev := kx.offsets.Access(0)
+		for r := 0; r < kx.kmers.Len(); r++ {
+			next := kx.offsets.Access(r + 1)
+			if !yield(decodeKmer(kx.kmers.Access(r), kx.k), int(next-prev)) {
+				return
+			}
+			prev = next
+		}
+	}
+}
+
+// rank returns the dictionary rank of kmer, canonicalized if the index is canonical.
+func (kx *KmerIndex) rank(kmer string) (int, bool) {
+	if len(kmer) != kx.k {
+		return 0, false
+	}
+	var fwd, rev uint64
+	for i := 0; i < len(kmer); i++ {
+		c, ok := nucleotideCode(kmer[i])
+		if !ok {
+			return 0, false
+		}
+		fwd = fwd<<2 | c
+		rev |= (3 - c) << uint(2*i)
+	}
+	if kx.canonical {
+		fwd = min(fwd, rev)
+	}
+	r, v := kx.kmers.NextGEQ(fwd)
+	return r, r >= 0 && v == fwd
+}
+
+// nucleotideCode returns the 2-bit code of an unambiguous base.
+func nucleotideCode(b byte) (uint64, bool) {
+	switch b {
+	case 'A', 'a':
+		return 0, true
+	case 'C', 'c':
+		return 1, true
+	case 'G', 'g':
+		return 2, true
+	case 'T', 't':
+		return 3, true
+	}
+	return 0, false
+}
+
+func decodeKmer(kmer uint64, k int) string {
+	buf := make([]byte, k)
+	for i := k - 1; i >= 0; i-- {
+		buf[i] = "ACGT"[kmer&3]
+		kmer >>= 2
+	}
+	return string(buf)
+}
@@ -0,0 +1,104 @@
+package succincter
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func reverseComplement(s string) string {
+	buf := make([]byte, len(s))
+	for i := range s {
+		buf[len(s)-1-i] = map[byte]byte{'A': 'T', 'C': 'G', 'G': 'C', 'T': 'A'}[s[i]]
+	}
+	return string(buf)
+}
+
+// naiveKmers maps each k-mer without ambiguous bases to its start positions.
+func naiveKmers(seq string, k int, canonical bool) map[string][]int {
+	out := make(map[string][]int)
+	for i := 0; i+k <= len(seq); i++ {
+		kmer := strings.ToUpper(seq[i : i+k])
+		if strings.Trim(kmer, "ACGT") != "" {
+			continue
+		}
+		if canonical {
+			kmer = min(kmer, reverseComplement(kmer))
+		}
+		out[kmer] = append(out[kmer], i)
+	}
+	return out
+}
+
+func TestKmerIndex(t *testing.T) {
+	rng := rand.New(rand.NewSource(44))
+	buf := make([]byte, 5000)
+	for i := range buf {
+		buf[i] = "ACGTacgtN"[rng.Intn(9)]
+	}
+	seqs := []string{"", "ACGT", "NNNN", "GATTACAGATTACA", string(buf)}
+
+	for _, seq := range seqs {
+		for _, k := range []int{1, 3, 7, 32} {
+			for _, canonical := range []bool{false, true} {
+				kx := NewKmerIndex([]byte(seq), k, canonical)
+				want := naiveKmers(seq, k, canonical)
+				total := 0
+				for kmer, positions := range want {
+					total += len(positions)
+					if got := kx.Count(kmer); got != len(positions) {
+						t.Fatalf("k=%d canonical=%v: Count(%s) = %d, want %d", k, canonical, kmer, got, len(positions))
+					}
+					if got := slices.Collect(kx.Positions(kmer)); !slices.Equal(got, positions) {
+						t.Fatalf("k=%d canonical=%v: Positions(%s) = %v, want %v", k, canonical, kmer, got, positions)
+					}
+				}
+				if kx.Distinct() != len(want) || kx.Total() != total {
+					t.Fatalf("k=%d canonical=%v: Distinct/Total = %d/%d, want %d/%d",
+						k, canonical, kx.Distinct(), kx.Total(), len(want), total)
+				}
+				var kmers []string
+				for kmer, count := range kx.All() {
+					kmers = append(kmers, kmer)
+					if count != len(want[kmer]) {
+						t.Fatalf("All: count of %s = %d, want %d", kmer, count, len(want[kmer]))
+					}
+				}
+				if wantKmers := slices.Sorted(maps.Keys(want)); !slices.Equal(kmers, wantKmers) {
+					t.Fatalf("k=%d canonical=%v: All yielded %d k-mers, want %d in order", k, canonical, len(kmers), len(wantKmers))
+				}
+			}
+		}
+	}
+}
+
+func TestKmerIndexCanonicalLookup(t *testing.T) {
+	kx := NewKmerIndex([]byte("AACGTTT"), 3, true)
+	// AAC and its reverse complement GTT are the same canonical k-mer.
+	if got, want := kx.Count("GTT"), kx.Count("AAC"); got != want || got != 2 {
+		t.Errorf("Count(GTT) = %d, Count(AAC) = %d; want 2 for both", got, want)
+	}
+	if got := slices.Collect(kx.Positions("gtt")); !slices.Equal(got, []int{0, 3}) {
+		t.Errorf("Positions(gtt) = %v; want [0 3]", got)
+	}
+	for _, bad := range []string{"", "AC", "ACGT", "ANA"} {
+		if got := kx.Count(bad); got != 0 {
+			t.Errorf("Count(%q) = %d; want 0", bad, got)
+		}
+	}
+}
+
+func TestKmerIndexInvalidK(t *testing.T) {
+	for _, k := range []int{0, 33} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewKmerIndex with k=%d did not panic", k)
+				}
+			}()
+			NewKmerIndex([]byte("ACGT"), k, false)
+		}()
+	}
+}
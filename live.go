@@ -0,0 +1,175 @@
+package succincter
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// liveChunkBits is the size of one LiveVersion chunk: 64 superblocks, 8 KiB of words.
+const liveChunkBits = 64 * superBlockSize
+
+// Update sets the bit at Pos to Bit. Used with Live.Apply.
+type Update struct {
+	Pos int
+	Bit bool
+}
+
+// Live holds the current version of a bitvector for readers on many goroutines while
+// writers publish new versions. Readers call Load (or Rank/Select) and always see one
+// complete, immutable version; writers never block readers.
+//
+// Each version is a slice of pointers to fixed-size chunks, each chunk its own
+// Succincter, plus a table of the 1-bit count before every chunk. Apply derives the
+// next version without re-evaluating any predicate: only the chunks touched by the
+// batch are copied and re-counted, every other chunk is shared with the previous
+// version, and the prefix table is rebuilt from the first touched chunk on. An update
+// therefore costs O(chunk + n/chunk) rather than O(n). Writers are serialized with
+// each other.
+type Live struct {
+	mu      sync.Mutex
+	current atomic.Pointer[LiveVersion]
+}
+
+// LiveVersion is one immutable version published by a Live, or a Growable snapshot.
+// It implements RankSelector; call Freeze for a contiguous Succincter.
+type LiveVersion struct {
+	chunks []*Succincter // chunk i covers positions [i*liveChunkBits, (i+1)*liveChunkBits)
+	ones   []int         // ones[i] is the number of 1-bits before chunk i; len(chunks)+1 entries
+	length int
+}
+
+// NewLive returns a Live whose current version holds the bits of s.
+func NewLive(s *Succincter) *Live {
+	l := &Live{}
+	l.current.Store(newLiveVersion(s))
+	return l
+}
+
+// newLiveVersion splits s into chunks. Chunk boundaries fall on word boundaries, so
+// each chunk's words are copied without shifting.
+func newLiveVersion(s *Succincter) *LiveVersion {
+	v := &LiveVersion{ones: []int{0}, length: s.length}
+	for lo := 0; lo < s.length; lo += liveChunkBits {
+		chunk := s.Slice(lo, min(lo+liveChunkBits, s.length))
+		v.chunks = append(v.chunks, chunk)
+		v.ones = append(v.ones, v.ones[len(v.ones)-1]+chunk.totalOnes)
+	}
+	return v
+}
+
+// Load returns the current version.
+func (l *Live) Load() *LiveVersion {
+	return l.current.Load()
+}
+
+// Rank returns the count of 1-bits before pos in the current version.
+func (l *Live) Rank(pos int) int {
+	return l.Load().Rank(pos)
+}
+
+// Select returns the position of the rank-th 1-bit in the current version.
+func (l *Live) Select(rank int) int {
+	return l.Load().Select(rank)
+}
+
+// Swap publishes the bits of s as the current version, for example after a full
+// rebuild, and returns the previous version.
+func (l *Live) Swap(s *Succincter) *LiveVersion {
+	next := newLiveVersion(s)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current.Swap(next)
+}
+
+// Apply publishes a new version with updates applied in order and returns it.
+// Panics if any position is outside [0, Len()) of the current version.
+func (l *Live) Apply(updates ...Update) *LiveVersion {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	old := l.current.Load()
+	if len(updates) == 0 {
+		return old
+	}
+
+	// Copy-on-write: a touched chunk gets its own words the first time the batch
+	// writes to it.
+	touched := make(map[int][]uint64)
+	for _, u := range updates {
+		if u.Pos < 0 || u.Pos >= old.length {
+			panic("succincter: Live update position out of range")
+		}
+		c := u.Pos / liveChunkBits
+		data, ok := touched[c]
+		if !ok {
+			data = slices.Clone(old.chunks[c].data)
+			touched[c] = data
+		}
+		off := u.Pos % liveChunkBits
+		mask := uint64(1) << uint(off%64)
+		if u.Bit {
+			data[off/64] |= mask
+		} else {
+			data[off/64] &^= mask
+		}
+	}
+
+	next := &LiveVersion{chunks: slices.Clone(old.chunks), length: old.length}
+	first := len(old.chunks)
+	for c, data := range touched {
+		next.chunks[c] = fromBitVector(data, old.chunks[c].length)
+		first = min(first, c)
+	}
+	next.ones = slices.Clone(old.ones)
+	for c := first; c < len(next.chunks); c++ {
+		next.ones[c+1] = next.ones[c] + next.chunks[c].totalOnes
+	}
+
+	l.current.Store(next)
+	return next
+}
+
+// Len returns the number of bits in the version.
+func (v *LiveVersion) Len() int {
+	return v.length
+}
+
+// Ones returns the total number of 1-bits in the version.
+func (v *LiveVersion) Ones() int {
+	return v.ones[len(v.ones)-1]
+}
+
+// Rank returns the count of 1-bits before pos. O(1) time.
+// Returns 0 for pos <= 0 and Ones() for pos >= Len().
+func (v *LiveVersion) Rank(pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	if pos >= v.length {
+		return v.Ones()
+	}
+	c := pos / liveChunkBits
+	return v.ones[c] + v.chunks[c].Rank(pos-c*liveChunkBits)
+}
+
+// Select returns the position of the rank-th 1-bit (1-indexed). O(log n) time.
+// Returns -1 for invalid ranks.
+func (v *LiveVersion) Select(rank int) int {
+	if rank <= 0 || rank > v.Ones() {
+		return -1
+	}
+	// First chunk whose cumulative count reaches rank.
+	c := sort.Search(len(v.chunks), func(c int) bool { return v.ones[c+1] >= rank })
+	return c*liveChunkBits + v.chunks[c].Select(rank-v.ones[c])
+}
+
+// Freeze returns a contiguous Succincter with the bits of the version. O(n) time.
+func (v *LiveVersion) Freeze() *Succincter {
+	data := make([]uint64, 0, (v.length+63)/64)
+	for _, chunk := range v.chunks {
+		data = append(data, chunk.data...)
+	}
+	return fromBitVector(data, v.length)
+}
@@ -0,0 +1,146 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLiveApplyMatchesRebuild(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	model := randomBools(rng, 5000)
+	l := NewLive(FromBools(model))
+
+	for batch := 0; batch < 50; batch++ {
+		updates := make([]Update, rng.Intn(20))
+		for i := range updates {
+			updates[i] = Update{Pos: rng.Intn(len(model)), Bit: rng.Intn(2) == 0}
+			model[updates[i].Pos] = updates[i].Bit
+		}
+		got := l.Apply(updates...)
+		if !reflect.DeepEqual(got.Freeze(), FromBools(model)) {
+			t.Fatalf("batch %d: Apply differs from rebuild", batch)
+		}
+		if l.Load() != got {
+			t.Fatalf("batch %d: Load did not return the applied version", batch)
+		}
+	}
+}
+
+func TestLiveApplySharesUntouchedChunks(t *testing.T) {
+	rng := rand.New(rand.NewSource(33))
+	model := randomBools(rng, 3*liveChunkBits+100)
+	l := NewLive(FromBools(model))
+	old := l.Load()
+
+	pos := liveChunkBits + 12345
+	model[pos] = !model[pos]
+	next := l.Apply(Update{Pos: pos, Bit: model[pos]})
+
+	for c, chunk := range next.chunks {
+		if shared := chunk == old.chunks[c]; shared != (c != 1) {
+			t.Errorf("chunk %d shared = %v; want %v", c, shared, c != 1)
+		}
+	}
+	want := FromBools(model)
+	for _, p := range []int{0, 1, liveChunkBits, pos, pos + 1, 2 * liveChunkBits, len(model) - 1, len(model)} {
+		if got := next.Rank(p); got != want.Rank(p) {
+			t.Errorf("Rank(%d) = %d; want %d", p, got, want.Rank(p))
+		}
+	}
+	for r := 1; r <= want.Ones(); r += 997 {
+		if got := next.Select(r); got != want.Select(r) {
+			t.Fatalf("Select(%d) = %d; want %d", r, got, want.Select(r))
+		}
+	}
+	if got := next.Select(want.Ones()); got != want.Select(want.Ones()) {
+		t.Errorf("Select(Ones()) = %d; want %d", got, want.Select(want.Ones()))
+	}
+}
+
+func TestLiveOldVersionsUnchanged(t *testing.T) {
+	l := NewLive(FromBools([]bool{true, false, true}))
+	old := l.Load()
+	l.Apply(Update{Pos: 1, Bit: true}, Update{Pos: 0, Bit: false})
+
+	if old.Ones() != 2 || old.Select(1) != 0 {
+		t.Errorf("old version changed: Ones=%d Select(1)=%d", old.Ones(), old.Select(1))
+	}
+	if got := l.Rank(3); got != 2 {
+		t.Errorf("Rank(3) = %d; want 2", got)
+	}
+	if got := l.Select(1); got != 1 {
+		t.Errorf("Select(1) = %d; want 1", got)
+	}
+
+	replacement := FromBools([]bool{false})
+	if prev := l.Swap(replacement); prev == old || prev.Len() != 3 {
+		t.Error("Swap returned the wrong previous version")
+	}
+	if l.Load().Len() != replacement.Len() {
+		t.Error("Swap did not publish the replacement")
+	}
+}
+
+func TestLiveConcurrentReadersSeeConsistentVersions(t *testing.T) {
+	// Every published version has exactly 100 ones; readers must never observe
+	// a version mid-update.
+	input := make([]bool, 10000)
+	for i := 0; i < 100; i++ {
+		input[i*100] = true
+	}
+	l := NewLive(FromBools(input))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := l.Load()
+				if s.Rank(s.Len()) != 100 || s.Select(100) == -1 {
+					t.Error("reader observed an inconsistent version")
+					return
+				}
+			}
+		}()
+	}
+
+	rng := rand.New(rand.NewSource(17))
+	for i := 0; i < 300; i++ {
+		from := l.Select(rng.Intn(100) + 1)
+		to := rng.Intn(len(input))
+		if l.Load().Rank(to+1)-l.Load().Rank(to) == 1 {
+			continue
+		}
+		l.Apply(Update{Pos: from, Bit: false}, Update{Pos: to, Bit: true})
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestLiveApplyOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Apply past Len did not panic")
+		}
+	}()
+	NewLive(FromBools(make([]bool, 10))).Apply(Update{Pos: 10, Bit: true})
+}
+
+func BenchmarkLiveApply(b *testing.B) {
+	rng := rand.New(rand.NewSource(18))
+	l := NewLive(FromBools(randomBools(rng, 1<<24)))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Apply(Update{Pos: rng.Intn(1 << 24), Bit: true})
+	}
+}
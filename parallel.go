@@ -0,0 +1,81 @@
+package succincter
+
+import (
+	"runtime"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// parallelThreshold is the input length below which NewSuccincterParallel falls
+// back to the sequential build; goroutine start-up dominates on smaller inputs.
+const parallelThreshold = 1 << 16
+
+// NewSuccincterParallel constructs a Succincter like NewSuccincter, evaluating the
+// predicate and building the rank directory on up to workers goroutines.
+// workers <= 0 uses runtime.GOMAXPROCS(0).
+//
+// Predicate evaluation is partitioned on word boundaries, so no two goroutines write
+// the same word and the result is identical to NewSuccincter for the same input and
+// predicate; the rank directory pass is then partitioned by superblock.
+// The predicate must be safe to call concurrently.
+func NewSuccincterParallel[T any](input []T, predicate func(T) bool, workers int) *Succincter {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 || len(input) < parallelThreshold {
+		return NewSuccincter(input, predicate)
+	}
+
+	data := internal.CompressToBitVectorParallel(input, predicate, workers)
+	blockRanks, superBlocks, totalOnes := precomputeRankParallel(data, blocksPerSuperBlock, workers)
+	return newSuccincter(data, blockRanks, superBlocks, totalOnes, len(input))
+}
+
+// precomputeRankParallel produces the same directory as precomputeRank in three passes:
+// per-superblock local ranks in parallel, a sequential prefix sum over superblock
+// totals, then a parallel pass adding each superblock's base to its blocks.
+func precomputeRankParallel(data []uint64, blocksPerSuperBlock, workers int) ([]uint64, []uint64, int) {
+	numSuperBlocks := (len(data) + blocksPerSuperBlock - 1) / blocksPerSuperBlock
+	blockRanks := make([]uint64, len(data))
+	superBlocks := make([]uint64, numSuperBlocks)
+	superTotals := make([]uint64, numSuperBlocks)
+
+	internal.ParallelRanges(numSuperBlocks, workers, func(lo, hi int) {
+		for sb := lo; sb < hi; sb++ {
+			start, end := superBlockSpan(sb, blocksPerSuperBlock, len(data))
+			local := uint64(0)
+			for i := start; i < end; i++ {
+				blockRanks[i] = local
+				local += uint64(internal.Popcount(data[i]))
+			}
+			superTotals[sb] = local
+		}
+	})
+
+	currentRank := uint64(0)
+	for sb, total := range superTotals {
+		superBlocks[sb] = currentRank
+		currentRank += total
+	}
+
+	internal.ParallelRanges(numSuperBlocks, workers, func(lo, hi int) {
+		for sb := lo; sb < hi; sb++ {
+			start, end := superBlockSpan(sb, blocksPerSuperBlock, len(data))
+			for i := start; i < end; i++ {
+				blockRanks[i] += superBlocks[sb]
+			}
+		}
+	})
+
+	return blockRanks, superBlocks, int(currentRank)
+}
+
+// superBlockSpan returns the half-open block range covered by superblock sb.
+func superBlockSpan(sb, blocksPerSuperBlock, numBlocks int) (int, int) {
+	start := sb * blocksPerSuperBlock
+	end := start + blocksPerSuperBlock
+	if end > numBlocks {
+		end = numBlocks
+	}
+	return start, end
+}
@@ -0,0 +1,50 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNewSuccincterParallelMatchesSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	sizes := []int{0, 1, 63, 64, 65, 1023, 1024, 1025, parallelThreshold - 1, parallelThreshold, parallelThreshold*3 + 17}
+
+	for _, n := range sizes {
+		input := make([]int, n)
+		for i := range input {
+			input[i] = rng.Intn(100)
+		}
+		pred := func(v int) bool { return v < 37 }
+
+		want := NewSuccincter(input, pred)
+		for _, workers := range []int{0, 1, 2, 3, 7, 64} {
+			got := NewSuccincterParallel(input, pred, workers)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("n=%d workers=%d: parallel build differs from sequential", n, workers)
+			}
+		}
+	}
+}
+
+func TestPrecomputeRankParallel(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	data := make([]uint64, 16*37+5)
+	for i := range data {
+		data[i] = rng.Uint64()
+	}
+
+	wantBlocks, wantSupers, wantTotal := precomputeRank(data, blocksPerSuperBlock)
+	for _, workers := range []int{2, 5, 100} {
+		gotBlocks, gotSupers, gotTotal := precomputeRankParallel(data, blocksPerSuperBlock, workers)
+		if !reflect.DeepEqual(gotBlocks, wantBlocks) {
+			t.Errorf("workers=%d: blockRanks differ", workers)
+		}
+		if !reflect.DeepEqual(gotSupers, wantSupers) {
+			t.Errorf("workers=%d: superBlocks differ", workers)
+		}
+		if gotTotal != wantTotal {
+			t.Errorf("workers=%d: totalOnes = %d; want %d", workers, gotTotal, wantTotal)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package succincter
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/shaia/succincter/internal"
+)
+
+// Chunk encodings of a PartitionedEF.
+const (
+	chunkEliasFano = iota // values - first as Elias-Fano
+	chunkBitmap           // bit v - first set in plain words
+	chunkRun              // first, first+1, ..., last: nothing stored
+)
+
+const (
+	// pefMaxChunk bounds the chunk lengths the partitioner tries.
+	pefMaxChunk = 4096
+	// pefChunkOverhead approximates the bits of chunk metadata: its entries in the
+	// start, first and last sequences plus the chunk header.
+	pefChunkOverhead = 128
+)
+
+// PartitionedEF stores a non-decreasing sequence of uint64 values split into chunks,
+// each encoded on its own as Elias-Fano, a dense bitmap, or a run of consecutive
+// values, whichever is smallest. Plain Elias-Fano spends about 2 + log2(u/n) bits per
+// value over the whole universe u; clustered sequences, such as posting lists of
+// bursty terms, are much denser within chunks, so local universes make them cheaper.
+//
+// Chunk boundaries minimize the estimated total size, including per-chunk overhead,
+// by dynamic programming over chunk lengths drawn from the series 1, 2, 3, 4, 6, 8,
+// 12, ... up to pefMaxChunk; any split point is reachable, and the search costs
+// O(n log pefMaxChunk). Chunk start indexes, first values and last values are
+// themselves Elias-Fano sequences, so locating a chunk is one successor query.
+type PartitionedEF struct {
+	chunks []pefChunk
+	starts *EFSequence // index of each chunk's first value, then n
+	firsts *EFSequence // first value of each chunk
+	lasts  *EFSequence // last value of each chunk
+	n      int
+}
+
+// pefChunkLengths are the chunk lengths the partitioner considers, ascending.
+var pefChunkLengths = func() []int {
+	lengths := []int{1}
+	for l := 2; l <= pefMaxChunk; l *= 2 {
+		lengths = append(lengths, l, l+l/2)
+	}
+	return slices.DeleteFunc(lengths, func(l int) bool { return l > pefMaxChunk })
+}()
+
+type pefChunk struct {
+	kind  int
+	ef    *EFSequence
+	words []uint64
+}
+
+// NewPartitionedEF encodes values. Panics if values are not non-decreasing.
+func NewPartitionedEF(values []uint64) *PartitionedEF {
+	for i := 1; i < len(values); i++ {
+		if values[i] < values[i-1] {
+			panic("succincter: PartitionedEF values must be non-decreasing")
+		}
+	}
+	// dups[i] counts equal adjacent pairs among values[:i], so a range is strictly
+	// increasing, and thus eligible for bitmap and run encoding, in O(1).
+	dups := make([]int, len(values)+1)
+	for i := 1; i < len(values); i++ {
+		dups[i+1] = dups[i]
+		if values[i] == values[i-1] {
+			dups[i+1]++
+		}
+	}
+	strict := func(lo, hi int) bool { return dups[hi]-dups[lo+1] == 0 }
+
+	// best[j] is the cheapest encoding of values[:j]; cut[j] is where its last
+	// chunk starts.
+	best := make([]int, len(values)+1)
+	cut := make([]int, len(values)+1)
+	for j := 1; j <= len(values); j++ {
+		best[j] = -1
+		for _, length := range pefChunkLengths {
+			if length > j {
+				break
+			}
+			_, cost := chunkEncoding(values, j-length, j, strict(j-length, j))
+			if cost += best[j-length] + pefChunkOverhead; best[j] < 0 || cost < best[j] {
+				best[j], cut[j] = cost, j-length
+			}
+		}
+	}
+	var bounds []int
+	for j := len(values); j > 0; j = cut[j] {
+		bounds = append(bounds, j)
+	}
+	slices.Reverse(bounds)
+
+	p := &PartitionedEF{n: len(values)}
+	var starts, firsts, lasts []uint64
+	lo := 0
+	for _, hi := range bounds {
+		p.chunks = append(p.chunks, encodeChunk(values[lo:hi], strict(lo, hi)))
+		starts = append(starts, uint64(lo))
+		firsts = append(firsts, values[lo])
+		lasts = append(lasts, values[hi-1])
+		lo = hi
+	}
+	p.starts = NewEFSequence(append(starts, uint64(len(values))))
+	p.firsts = NewEFSequence(firsts)
+	p.lasts = NewEFSequence(lasts)
+	return p
+}
+
+// chunkEncoding picks the cheapest encoding for values[lo:hi] and returns it with
+// its estimated size in bits. Bitmaps and runs need strictly increasing values.
+func chunkEncoding(values []uint64, lo, hi int, strict bool) (kind, cost int) {
+	m := hi - lo
+	u := values[hi-1] - values[lo]
+	if strict && u == uint64(m-1) {
+		return chunkRun, 0
+	}
+	lowBits := 0
+	if u/uint64(m) > 0 {
+		lowBits = bits.Len64(u/uint64(m)) - 1
+	}
+	kind, cost = chunkEliasFano, m*lowBits+m+int(u>>uint(lowBits))+1
+	// Bitmap chunks carry no rank directory: they are only chosen when dense, so
+	// scanning their few words is cheap.
+	if strict && u < uint64(cost) {
+		kind, cost = chunkBitmap, int(u+1)
+	}
+	return kind, cost
+}
+
+func encodeChunk(values []uint64, strict bool) pefChunk {
+	kind, _ := chunkEncoding(values, 0, len(values), strict)
+	first := values[0]
+	switch kind {
+	case chunkRun:
+		return pefChunk{kind: chunkRun}
+	case chunkBitmap:
+		words := make([]uint64, (values[len(values)-1]-first)/64+1)
+		for _, v := range values {
+			words[(v-first)/64] |= 1 << ((v - first) % 64)
+		}
+		return pefChunk{kind: chunkBitmap, words: words}
+	}
+	offsets := make([]uint64, len(values))
+	for i, v := range values {
+		offsets[i] = v - first
+	}
+	return pefChunk{kind: chunkEliasFano, ef: NewEFSequence(offsets)}
+}
+
+// Len returns the number of values.
+func (p *PartitionedEF) Len() int {
+	return p.n
+}
+
+// Chunks returns the number of chunks the sequence was split into.
+func (p *PartitionedEF) Chunks() int {
+	return len(p.chunks)
+}
+
+// Access returns value i. Panics if i is outside [0, Len()).
+func (p *PartitionedEF) Access(i int) uint64 {
+	if i < 0 || i >= p.n {
+		panic("succincter: PartitionedEF index out of range")
+	}
+	c, start := p.starts.PrevLEQ(uint64(i))
+	k := i - int(start)
+	first := p.firsts.Access(c)
+	switch ch := p.chunks[c]; ch.kind {
+	case chunkRun:
+		return first + uint64(k)
+	case chunkBitmap:
+		for w, word := range ch.words {
+			if ones := internal.Popcount(word); k >= ones {
+				k -= ones
+				continue
+			}
+			return first + uint64(w*64+internal.SelectInBlock(word, k+1))
+		}
+		panic("succincter: PartitionedEF bitmap chunk has too few values")
+	default:
+		return first + ch.ef.Access(k)
+	}
+}
+
+// NextGEQ returns the index and value of the first value >= x, or (-1, 0) if every
+// value is smaller.
+func (p *PartitionedEF) NextGEQ(x uint64) (int, uint64) {
+	c, _ := p.lasts.NextGEQ(x)
+	if c < 0 {
+		return -1, 0
+	}
+	start := int(p.starts.Access(c))
+	first := p.firsts.Access(c)
+	off := uint64(0)
+	if x > first {
+		off = x - first
+	}
+	switch ch := p.chunks[c]; ch.kind {
+	case chunkRun:
+		return start + int(off), first + off
+	case chunkBitmap:
+		// The chunk's last value is >= x, so a 1-bit at or after off exists.
+		w := int(off / 64)
+		word := ch.words[w] &^ (1<<(off%64) - 1)
+		rank := 0
+		for _, before := range ch.words[:w] {
+			rank += internal.Popcount(before)
+		}
+		for word == 0 {
+			rank += internal.Popcount(ch.words[w])
+			w++
+			word = ch.words[w]
+		}
+		rank += internal.Popcount(ch.words[w] & (word&-word - 1))
+		return start + rank, first + uint64(w*64+internal.TrailingZeros(word))
+	default:
+		k, v := ch.ef.NextGEQ(off)
+		return start + k, first + v
+	}
+}
+
+// Rank returns the number of values smaller than x.
+func (p *PartitionedEF) Rank(x uint64) int {
+	i, _ := p.NextGEQ(x)
+	if i < 0 {
+		return p.n
+	}
+	return i
+}
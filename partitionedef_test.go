@@ -0,0 +1,130 @@
+package succincter
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// clusteredValues returns a sorted sequence mixing sparse gaps, dense bursts and
+// runs of consecutive values, the shape partitioning is meant for.
+func clusteredValues(rng *rand.Rand, n int) []uint64 {
+	values := make([]uint64, 0, n)
+	v := uint64(0)
+	for len(values) < n {
+		switch rng.Intn(3) {
+		case 0: // sparse
+			for k := 0; k < 200 && len(values) < n; k++ {
+				v += 1 + uint64(rng.Intn(100_000))
+				values = append(values, v)
+			}
+		case 1: // dense burst
+			for k := 0; k < 500 && len(values) < n; k++ {
+				v += 1 + uint64(rng.Intn(3))
+				values = append(values, v)
+			}
+		default: // run
+			for k := 0; k < 300 && len(values) < n; k++ {
+				v++
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func TestPartitionedEF(t *testing.T) {
+	rng := rand.New(rand.NewSource(49))
+	inputs := [][]uint64{
+		nil,
+		{0},
+		{7, 7, 7},
+		{1, 2, 3, 4, 5},
+		clusteredValues(rng, 20_000),
+	}
+	withDups := clusteredValues(rng, 5000)
+	for i := 1; i < len(withDups); i += 7 {
+		withDups[i] = withDups[i-1]
+	}
+	inputs = append(inputs, withDups)
+
+	for _, values := range inputs {
+		p := NewPartitionedEF(values)
+		if p.Len() != len(values) {
+			t.Fatalf("Len() = %d; want %d", p.Len(), len(values))
+		}
+		for i, want := range values {
+			if got := p.Access(i); got != want {
+				t.Fatalf("n=%d: Access(%d) = %d; want %d", len(values), i, got, want)
+			}
+		}
+		probes := []uint64{0, 1, 6, 7, 8}
+		if len(values) > 0 {
+			last := values[len(values)-1]
+			probes = append(probes, last, last+1)
+			for k := 0; k < 500; k++ {
+				probes = append(probes, uint64(rng.Int63n(int64(last)+2)))
+			}
+		}
+		for _, x := range probes {
+			want := sort.Search(len(values), func(i int) bool { return values[i] >= x })
+			i, v := p.NextGEQ(x)
+			if want == len(values) {
+				if i != -1 || v != 0 {
+					t.Fatalf("n=%d: NextGEQ(%d) = (%d, %d); want (-1, 0)", len(values), x, i, v)
+				}
+			} else if i != want || v != values[want] {
+				t.Fatalf("n=%d: NextGEQ(%d) = (%d, %d); want (%d, %d)", len(values), x, i, v, want, values[want])
+			}
+			if got := p.Rank(x); got != want {
+				t.Fatalf("n=%d: Rank(%d) = %d; want %d", len(values), x, got, want)
+			}
+		}
+	}
+}
+
+func TestPartitionedEFUsesAllEncodings(t *testing.T) {
+	p := NewPartitionedEF(clusteredValues(rand.New(rand.NewSource(49)), 50_000))
+	seen := make(map[int]bool)
+	for _, c := range p.chunks {
+		seen[c.kind] = true
+	}
+	for _, kind := range []int{chunkEliasFano, chunkBitmap, chunkRun} {
+		if !seen[kind] {
+			t.Errorf("no chunk of kind %d among %d chunks", kind, p.Chunks())
+		}
+	}
+}
+
+func TestPartitionedEFPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"decreasing", func() { NewPartitionedEF([]uint64{3, 2}) }},
+		{"Access at end", func() { NewPartitionedEF([]uint64{1, 2}).Access(2) }},
+		{"Access negative", func() { NewPartitionedEF([]uint64{1, 2}).Access(-1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func BenchmarkPartitionedEFNextGEQ(b *testing.B) {
+	rng := rand.New(rand.NewSource(49))
+	values := clusteredValues(rng, 1_000_000)
+	p := NewPartitionedEF(values)
+	last := int64(values[len(values)-1])
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.NextGEQ(uint64(rng.Int63n(last)))
+	}
+}
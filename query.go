@@ -0,0 +1,170 @@
+package succincter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// queryNode is a parsed boolean query over named bitvectors.
+type queryNode interface{}
+
+type (
+	queryName struct{ name string }
+	queryNot  struct{ operand queryNode }
+	queryAnd  struct{ operands []queryNode }
+	queryOr   struct{ operands []queryNode }
+)
+
+type queryToken struct {
+	kind string // "name", "and", "or", "not", "(", ")", "eof"
+	text string
+	pos  int
+}
+
+// parseQuery parses expressions such as "online AND (premium OR trial) AND NOT banned".
+// Keywords are case-insensitive; &&, || and ! are accepted as synonyms. Precedence
+// from tightest: NOT, AND, OR.
+func parseQuery(expr string) (queryNode, error) {
+	tokens, err := tokenizeQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != "eof" {
+		return nil, fmt.Errorf("succincter: unexpected %q at offset %d in query", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+func tokenizeQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, queryToken{kind: string(c), text: string(c), pos: i})
+			i++
+		case c == '!':
+			tokens = append(tokens, queryToken{kind: "not", text: "!", pos: i})
+			i++
+		case strings.HasPrefix(expr[i:], "&&"):
+			tokens = append(tokens, queryToken{kind: "and", text: "&&", pos: i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, queryToken{kind: "or", text: "||", pos: i})
+			i += 2
+		case isQueryNameChar(c):
+			start := i
+			for i < len(expr) && isQueryNameChar(rune(expr[i])) {
+				i++
+			}
+			word := expr[start:i]
+			kind := "name"
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = "and"
+			case "OR":
+				kind = "or"
+			case "NOT":
+				kind = "not"
+			}
+			tokens = append(tokens, queryToken{kind: kind, text: word, pos: start})
+		default:
+			return nil, fmt.Errorf("succincter: unexpected character %q at offset %d in query", c, i)
+		}
+	}
+	return append(tokens, queryToken{kind: "eof", text: "end of query", pos: len(expr)}), nil
+}
+
+func isQueryNameChar(c rune) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' || c == ':')
+}
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) advance() queryToken {
+	tok := p.tokens[p.next]
+	if tok.kind != "eof" {
+		p.next++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []queryNode{first}
+	for p.peek().kind == "or" {
+		p.advance()
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return queryOr{operands: operands}, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := []queryNode{first}
+	for p.peek().kind == "and" {
+		p.advance()
+		next, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return queryAnd{operands: operands}, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case "not":
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{operand: operand}, nil
+	case "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != ")" {
+			return nil, fmt.Errorf("succincter: expected ) at offset %d in query, got %q", closing.pos, closing.text)
+		}
+		return node, nil
+	case "name":
+		return queryName{name: tok.text}, nil
+	default:
+		return nil, fmt.Errorf("succincter: unexpected %q at offset %d in query", tok.text, tok.pos)
+	}
+}
@@ -0,0 +1,59 @@
+package succincter
+
+import "iter"
+
+// Runs returns an iterator over maximal runs of consecutive 1-bits at least minLen
+// long, yielding each run's start position and length in ascending order.
+//
+// Runs are found with NextOne and NextZero, which check the current word and then
+// jump with the rank directory, so long stretches of either bit are skipped rather
+// than walked bit by bit.
+func (s *Succincter) Runs(minLen int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for start := s.NextOne(0); start != -1; {
+			end := s.NextZero(start)
+			if end == -1 {
+				end = s.length
+			}
+			if end-start >= minLen && !yield(start, end-start) {
+				return
+			}
+			start = s.NextOne(end)
+		}
+	}
+}
+
+// LongestRun returns the start and length of the longest run of consecutive bits
+// equal to bit, or (-1, 0) if there is none. Ties go to the earliest run.
+func (s *Succincter) LongestRun(bit bool) (start, length int) {
+	start = -1
+	next, other := s.NextOne, s.NextZero
+	if !bit {
+		next, other = s.NextZero, s.NextOne
+	}
+	for pos := next(0); pos != -1; {
+		end := other(pos)
+		if end == -1 {
+			end = s.length
+		}
+		if end-pos > length {
+			start, length = pos, end-pos
+		}
+		pos = next(end)
+	}
+	return start, length
+}
+
+// GapHistogram returns, for each gap size, how many pairs of consecutive 1-bits have
+// exactly that many 0-bits between them. Leading and trailing 0-bits are not gaps.
+func (s *Succincter) GapHistogram() map[int]int {
+	hist := make(map[int]int)
+	prev := -1
+	for pos := range s.Positions() {
+		if prev != -1 {
+			hist[pos-prev-1]++
+		}
+		prev = pos
+	}
+	return hist
+}
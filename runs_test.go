@@ -0,0 +1,109 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// bruteRuns returns [start, length] pairs for maximal runs of bit in input.
+func bruteRuns(input []bool, bit bool) [][2]int {
+	var runs [][2]int
+	for i := 0; i < len(input); i++ {
+		if input[i] == bit {
+			j := i
+			for j < len(input) && input[j] == bit {
+				j++
+			}
+			runs = append(runs, [2]int{i, j - i})
+			i = j
+		}
+	}
+	return runs
+}
+
+func burstyBools(rng *rand.Rand, n int) []bool {
+	input := make([]bool, n)
+	for i := 0; i < n; {
+		length := 1 + rng.Intn(200)
+		bit := rng.Intn(3) == 0
+		for j := i; j < i+length && j < n; j++ {
+			input[j] = bit
+		}
+		i += length
+	}
+	return input
+}
+
+func TestNextZero(t *testing.T) {
+	rng := rand.New(rand.NewSource(34))
+	input := burstyBools(rng, 3000)
+	s := FromBools(input)
+
+	for pos := -1; pos <= len(input)+64; pos++ {
+		want := -1
+		for i := max(pos, 0); i < len(input); i++ {
+			if !input[i] {
+				want = i
+				break
+			}
+		}
+		if got := s.NextZero(pos); got != want {
+			t.Fatalf("NextZero(%d) = %d; want %d", pos, got, want)
+		}
+	}
+}
+
+func TestRuns(t *testing.T) {
+	rng := rand.New(rand.NewSource(35))
+	input := burstyBools(rng, 5000)
+	s := FromBools(input)
+
+	for _, minLen := range []int{0, 1, 50, 150} {
+		var want [][2]int
+		for _, r := range bruteRuns(input, true) {
+			if r[1] >= minLen {
+				want = append(want, r)
+			}
+		}
+		var got [][2]int
+		for start, length := range s.Runs(minLen) {
+			got = append(got, [2]int{start, length})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Runs(%d) differs from brute force: got %d runs, want %d", minLen, len(got), len(want))
+		}
+	}
+}
+
+func TestLongestRun(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	for trial := 0; trial < 10; trial++ {
+		input := burstyBools(rng, 500+rng.Intn(3000))
+		s := FromBools(input)
+		for _, bit := range []bool{true, false} {
+			wantStart, wantLen := -1, 0
+			for _, r := range bruteRuns(input, bit) {
+				if r[1] > wantLen {
+					wantStart, wantLen = r[0], r[1]
+				}
+			}
+			if start, length := s.LongestRun(bit); start != wantStart || length != wantLen {
+				t.Errorf("LongestRun(%v) = (%d, %d); want (%d, %d)", bit, start, length, wantStart, wantLen)
+			}
+		}
+	}
+
+	allOnes := FromBools([]bool{true, true, true})
+	if start, length := allOnes.LongestRun(false); start != -1 || length != 0 {
+		t.Errorf("LongestRun(false) on all ones = (%d, %d); want (-1, 0)", start, length)
+	}
+}
+
+func TestGapHistogram(t *testing.T) {
+	s := FromBools([]bool{false, true, false, false, true, true, false, false, true, false})
+	want := map[int]int{2: 2, 0: 1}
+	if got := s.GapHistogram(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GapHistogram() = %v; want %v", got, want)
+	}
+}
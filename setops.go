@@ -0,0 +1,76 @@
+package succincter
+
+import "github.com/shaia/succincter/internal"
+
+// And returns a new Succincter whose bits are a AND b, computed a word at a time.
+// Panics if a and b have different lengths.
+func And(a, b *Succincter) *Succincter {
+	return combine(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+// Or returns a new Succincter whose bits are a OR b.
+// Panics if a and b have different lengths.
+func Or(a, b *Succincter) *Succincter {
+	return combine(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+// Xor returns a new Succincter whose bits are a XOR b.
+// Panics if a and b have different lengths.
+func Xor(a, b *Succincter) *Succincter {
+	return combine(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// AndNot returns a new Succincter whose bits are a AND NOT b.
+// Panics if a and b have different lengths.
+func AndNot(a, b *Succincter) *Succincter {
+	return combine(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// Not returns a new Succincter with every bit of a in [0, a.Len()) inverted.
+func Not(a *Succincter) *Succincter {
+	data := make([]uint64, len(a.data))
+	for i, w := range a.data {
+		data[i] = ^w
+	}
+	clearTail(data, a.length)
+	return fromBitVector(data, a.length)
+}
+
+// CountAnd returns the number of positions set in both a and b without allocating.
+// Panics if a and b have different lengths.
+func CountAnd(a, b *Succincter) int {
+	checkSameLength(a, b)
+	count := 0
+	for i, w := range a.data {
+		count += internal.Popcount(w & b.data[i])
+	}
+	return count
+}
+
+// CountOr returns the number of positions set in a or b without allocating.
+// Panics if a and b have different lengths.
+func CountOr(a, b *Succincter) int {
+	return a.totalOnes + b.totalOnes - CountAnd(a, b)
+}
+
+func combine(a, b *Succincter, op func(x, y uint64) uint64) *Succincter {
+	checkSameLength(a, b)
+	data := make([]uint64, len(a.data))
+	for i, w := range a.data {
+		data[i] = op(w, b.data[i])
+	}
+	return fromBitVector(data, a.length)
+}
+
+func checkSameLength(a, b *Succincter) {
+	if a.length != b.length {
+		panic("succincter: operands have different lengths")
+	}
+}
+
+// clearTail zeroes the bits of data at or beyond length.
+func clearTail(data []uint64, length int) {
+	if tail := length % 64; tail != 0 {
+		data[len(data)-1] &= (uint64(1) << uint(tail)) - 1
+	}
+}
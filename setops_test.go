@@ -0,0 +1,86 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+
+	for _, n := range []int{0, 1, 63, 64, 1000, 5000} {
+		x := randomBools(rng, n)
+		y := randomBools(rng, n)
+		a, b := FromBools(x), FromBools(y)
+
+		tests := []struct {
+			name string
+			got  *Succincter
+			op   func(p, q bool) bool
+		}{
+			{"And", And(a, b), func(p, q bool) bool { return p && q }},
+			{"Or", Or(a, b), func(p, q bool) bool { return p || q }},
+			{"Xor", Xor(a, b), func(p, q bool) bool { return p != q }},
+			{"AndNot", AndNot(a, b), func(p, q bool) bool { return p && !q }},
+			{"Not", Not(a), func(p, _ bool) bool { return !p }},
+		}
+
+		for _, tt := range tests {
+			want := make([]bool, n)
+			for i := range want {
+				want[i] = tt.op(x[i], y[i])
+			}
+			if !reflect.DeepEqual(tt.got, FromBools(want)) {
+				t.Errorf("n=%d: %s differs from element-wise result", n, tt.name)
+			}
+		}
+
+		and, or := 0, 0
+		for i := range x {
+			if x[i] && y[i] {
+				and++
+			}
+			if x[i] || y[i] {
+				or++
+			}
+		}
+		if got := CountAnd(a, b); got != and {
+			t.Errorf("n=%d: CountAnd = %d; want %d", n, got, and)
+		}
+		if got := CountOr(a, b); got != or {
+			t.Errorf("n=%d: CountOr = %d; want %d", n, got, or)
+		}
+	}
+}
+
+func TestNotKeepsLength(t *testing.T) {
+	s := Not(FromBools([]bool{true, false, false}))
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d; want 3", s.Len())
+	}
+	if got := s.Rank(1000); got != 2 {
+		t.Errorf("Rank(1000) = %d; want 2 (no padding bits set)", got)
+	}
+}
+
+func TestSetOperationsLengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("And on different lengths did not panic")
+		}
+	}()
+	And(FromBools(make([]bool, 10)), FromBools(make([]bool, 11)))
+}
+
+func BenchmarkCountAnd(b *testing.B) {
+	rng := rand.New(rand.NewSource(9))
+	x := FromBools(randomBools(rng, 1_000_000))
+	y := FromBools(randomBools(rng, 1_000_000))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CountAnd(x, y)
+	}
+}
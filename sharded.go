@@ -0,0 +1,114 @@
+package succincter
+
+import "sort"
+
+// Sharded presents several RankSelectors as one logical bitvector: their
+// concatenation in shard order. Shards may use different encodings, for example a
+// Succincter per daily log file alongside a Growable snapshot for today.
+//
+// A prefix table of shard lengths and 1-bit counts routes each query to one shard
+// with a binary search, so Rank and Select cost O(log shards) plus the shard's own
+// query. Appending or replacing a shard touches only the prefix table.
+//
+// The table is filled in when a shard is added, so shards must not change
+// afterwards: after mutating one, such as a DynamicBitvector, call Replace with it
+// and its new length, or Rank and Select return wrong answers.
+//
+// Reads are safe to run concurrently with each other, but not with Append or Replace.
+type Sharded struct {
+	shards    []RankSelector
+	positions []int // positions[i] is the first logical position of shard i; len(shards)+1 entries
+	ones      []int // ones[i] is the number of 1-bits before shard i; len(shards)+1 entries
+}
+
+// NewSharded returns an empty Sharded. The zero value is also ready to use.
+func NewSharded() *Sharded {
+	return &Sharded{}
+}
+
+// Append adds rs, covering length bits, after the existing shards. Its length and
+// 1-bit count are recorded now; rs must not change until it is replaced.
+func (s *Sharded) Append(rs RankSelector, length int) {
+	if length < 0 {
+		panic("succincter: Sharded shard length is negative")
+	}
+	if len(s.positions) == 0 {
+		s.positions = []int{0}
+		s.ones = []int{0}
+	}
+	s.shards = append(s.shards, rs)
+	s.positions = append(s.positions, s.Len()+length)
+	s.ones = append(s.ones, s.Ones()+rs.Rank(length))
+}
+
+// Replace swaps shard i for rs, covering length bits. Other shards are untouched;
+// only the prefix entries after i are recomputed. Panics if i is out of range.
+func (s *Sharded) Replace(i int, rs RankSelector, length int) {
+	if i < 0 || i >= len(s.shards) {
+		panic("succincter: Sharded shard index out of range")
+	}
+	if length < 0 {
+		panic("succincter: Sharded shard length is negative")
+	}
+	oldLength := s.positions[i+1] - s.positions[i]
+	oldOnes := s.ones[i+1] - s.ones[i]
+	lengthDelta := length - oldLength
+	onesDelta := rs.Rank(length) - oldOnes
+
+	s.shards[i] = rs
+	for j := i + 1; j < len(s.positions); j++ {
+		s.positions[j] += lengthDelta
+		s.ones[j] += onesDelta
+	}
+}
+
+// NumShards returns the number of shards.
+func (s *Sharded) NumShards() int {
+	return len(s.shards)
+}
+
+// Shard returns shard i and the logical position of its first bit.
+func (s *Sharded) Shard(i int) (RankSelector, int) {
+	return s.shards[i], s.positions[i]
+}
+
+// Len returns the total number of bits across all shards.
+func (s *Sharded) Len() int {
+	if len(s.positions) == 0 {
+		return 0
+	}
+	return s.positions[len(s.positions)-1]
+}
+
+// Ones returns the total number of 1-bits across all shards.
+func (s *Sharded) Ones() int {
+	if len(s.ones) == 0 {
+		return 0
+	}
+	return s.ones[len(s.ones)-1]
+}
+
+// Rank returns the count of 1-bits before logical position pos.
+// Returns 0 for pos <= 0 and Ones() for pos >= Len().
+func (s *Sharded) Rank(pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	if pos >= s.Len() {
+		return s.Ones()
+	}
+	// Last shard starting at or before pos.
+	i := sort.Search(len(s.shards), func(i int) bool { return s.positions[i+1] > pos })
+	return s.ones[i] + s.shards[i].Rank(pos-s.positions[i])
+}
+
+// Select returns the logical position of the rank-th 1-bit (1-indexed).
+// Returns -1 for invalid ranks.
+func (s *Sharded) Select(rank int) int {
+	if rank <= 0 || rank > s.Ones() {
+		return -1
+	}
+	// First shard whose cumulative count reaches rank.
+	i := sort.Search(len(s.shards), func(i int) bool { return s.ones[i+1] >= rank })
+	return s.positions[i] + s.shards[i].Select(rank-s.ones[i])
+}
@@ -0,0 +1,91 @@
+package succincter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkRankSelect(t *testing.T, rs RankSelector, model []bool) {
+	t.Helper()
+	rank := 0
+	for i, v := range model {
+		if got := rs.Rank(i); got != rank {
+			t.Fatalf("Rank(%d) = %d; want %d", i, got, rank)
+		}
+		if v {
+			rank++
+			if got := rs.Select(rank); got != i {
+				t.Fatalf("Select(%d) = %d; want %d", rank, got, i)
+			}
+		}
+	}
+	if got := rs.Rank(len(model)); got != rank {
+		t.Fatalf("Rank(%d) = %d; want %d", len(model), got, rank)
+	}
+	if got := rs.Select(rank + 1); got != -1 {
+		t.Fatalf("Select(%d) = %d; want -1", rank+1, got)
+	}
+}
+
+func TestShardedMixedEncodings(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	var model []bool
+	s := NewSharded()
+
+	for i := 0; i < 6; i++ {
+		part := randomBools(rng, rng.Intn(300))
+		model = append(model, part...)
+		switch i % 3 {
+		case 0:
+			s.Append(FromBools(part), len(part))
+		case 1:
+			d := NewDynamicBitvector()
+			for j, v := range part {
+				d.Insert(j, v)
+			}
+			s.Append(d, len(part))
+		default:
+			g := NewGrowable()
+			g.Append(part...)
+			s.Append(g, len(part))
+		}
+	}
+
+	if s.NumShards() != 6 || s.Len() != len(model) {
+		t.Fatalf("NumShards=%d Len=%d; want 6, %d", s.NumShards(), s.Len(), len(model))
+	}
+	checkRankSelect(t, s, model)
+}
+
+func TestShardedReplace(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	parts := [][]bool{randomBools(rng, 100), randomBools(rng, 200), randomBools(rng, 150)}
+	var s Sharded
+	for _, p := range parts {
+		s.Append(FromBools(p), len(p))
+	}
+
+	parts[1] = randomBools(rng, 77)
+	s.Replace(1, FromBools(parts[1]), len(parts[1]))
+
+	var model []bool
+	for _, p := range parts {
+		model = append(model, p...)
+	}
+	checkRankSelect(t, &s, model)
+
+	if _, start := s.Shard(2); start != 177 {
+		t.Errorf("Shard(2) starts at %d; want 177", start)
+	}
+}
+
+func TestShardedEmpty(t *testing.T) {
+	var s Sharded
+	if s.Len() != 0 || s.Ones() != 0 || s.Rank(5) != 0 || s.Select(1) != -1 {
+		t.Error("empty Sharded returned non-empty results")
+	}
+	s.Append(FromBools(nil), 0)
+	s.Append(FromBools([]bool{false, true}), 2)
+	s.Append(FromBools(nil), 0)
+	checkRankSelect(t, &s, []bool{false, true})
+}
@@ -1,6 +1,11 @@
 package succincter
 
-import "github.com/shaia/succincter/internal"
+import (
+	"iter"
+	"sort"
+
+	"github.com/shaia/succincter/internal"
+)
 
 // RankSelector is the interface for data structures supporting rank and select queries.
 type RankSelector interface {
@@ -18,18 +23,29 @@ type Succincter struct {
 	superBlockSize     int
 	blocksPerSuperBlock int
 	totalOnes          int
+	length             int
 }
 
+const (
+	blockSize           = 64
+	superBlockSize      = 1024
+	blocksPerSuperBlock = superBlockSize / blockSize
+)
+
 // NewSuccincter constructs a Succincter from any slice using a predicate to determine 1-bits.
 // Construction is O(n).
 func NewSuccincter[T any](input []T, predicate func(T) bool) *Succincter {
-	blockSize := 64
-	superBlockSize := 1024
-	blocksPerSuperBlock := superBlockSize / blockSize
+	return fromBitVector(internal.CompressToBitVector(input, predicate), len(input))
+}
 
-	data := internal.CompressToBitVector(input, predicate)
+// fromBitVector builds the rank directory over the first length bits of data.
+// Bits of data at or beyond length must be zero. The Succincter takes ownership of data.
+func fromBitVector(data []uint64, length int) *Succincter {
 	blockRanks, superBlocks, totalOnes := precomputeRank(data, blocksPerSuperBlock)
+	return newSuccincter(data, blockRanks, superBlocks, totalOnes, length)
+}
 
+func newSuccincter(data, blockRanks, superBlocks []uint64, totalOnes, length int) *Succincter {
 	return &Succincter{
 		data:               data,
 		blockRanks:         blockRanks,
@@ -38,9 +54,20 @@ func NewSuccincter[T any](input []T, predicate func(T) bool) *Succincter {
 		superBlockSize:     superBlockSize,
 		blocksPerSuperBlock: blocksPerSuperBlock,
 		totalOnes:          totalOnes,
+		length:             length,
 	}
 }
 
+// Len returns the number of bits (elements) indexed.
+func (s *Succincter) Len() int {
+	return s.length
+}
+
+// Ones returns the total number of 1-bits.
+func (s *Succincter) Ones() int {
+	return s.totalOnes
+}
+
 // Rank returns the count of 1-bits before position pos. O(1) time.
 // Returns 0 for pos <= 0 or empty arrays.
 func (s *Succincter) Rank(pos int) int {
@@ -88,16 +115,77 @@ func (s *Succincter) Select(rank int) int {
 	return absoluteBlockIndex*s.blockSize + internal.SelectInBlock(s.data[absoluteBlockIndex], blockRank)
 }
 
+// Select0 returns the position of the rank-th 0-bit (1-indexed) within [0, Len()).
+// O(log n) time. Returns -1 for invalid ranks.
+func (s *Succincter) Select0(rank int) int {
+	if rank <= 0 || rank > s.length-s.totalOnes {
+		return -1
+	}
+	zerosBefore := func(block int) int {
+		return block*s.blockSize - int(s.blockRanks[block])
+	}
+	// Last block with fewer than rank zeros before it.
+	blockIndex := sort.Search(len(s.data), func(b int) bool { return zerosBefore(b) >= rank }) - 1
+	return blockIndex*s.blockSize + internal.SelectInBlock(^s.data[blockIndex], rank-zerosBefore(blockIndex))
+}
+
+// NextOne returns the position of the first 1-bit at or after pos, or -1 if there is none.
+// Checks the word containing pos directly, then falls back to Rank and Select. O(log n) time.
+func (s *Succincter) NextOne(pos int) int {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(s.data)*s.blockSize {
+		return -1
+	}
+	blockIndex := pos / s.blockSize
+	if w := s.data[blockIndex] >> uint(pos%s.blockSize); w != 0 {
+		return pos + internal.TrailingZeros(w)
+	}
+	return s.Select(s.Rank((blockIndex+1)*s.blockSize) + 1)
+}
+
+// NextZero returns the position of the first 0-bit at or after pos within [0, Len()),
+// or -1 if there is none. O(log n) time.
+func (s *Succincter) NextZero(pos int) int {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= s.length {
+		return -1
+	}
+	blockIndex := pos / s.blockSize
+	if w := ^s.data[blockIndex] >> uint(pos%s.blockSize); w != 0 {
+		if next := pos + internal.TrailingZeros(w); next < s.length {
+			return next
+		}
+		return -1
+	}
+	next := (blockIndex + 1) * s.blockSize
+	return s.Select0(next - s.Rank(next) + 1)
+}
+
+// Positions returns an iterator over the positions of all 1-bits in ascending order.
+func (s *Succincter) Positions() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for pos := s.NextOne(0); pos != -1; pos = s.NextOne(pos + 1) {
+			if !yield(pos) {
+				return
+			}
+		}
+	}
+}
+
 func precomputeRank(data []uint64, blocksPerSuperBlock int) ([]uint64, []uint64, int) {
-	var blockRanks []uint64
-	var superBlocks []uint64
+	blockRanks := make([]uint64, len(data))
+	superBlocks := make([]uint64, 0, (len(data)+blocksPerSuperBlock-1)/blocksPerSuperBlock)
 	currentRank := uint64(0)
 
 	for i, block := range data {
 		if i%blocksPerSuperBlock == 0 {
 			superBlocks = append(superBlocks, currentRank)
 		}
-		blockRanks = append(blockRanks, currentRank)
+		blockRanks[i] = currentRank
 		currentRank += uint64(internal.Popcount(block))
 	}
 
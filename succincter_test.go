@@ -478,3 +478,49 @@ func TestEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestPositions(t *testing.T) {
+	input := []bool{false, true, true, false, false, true}
+	s := NewSuccincter(input, func(b bool) bool { return b })
+
+	var got []int
+	for pos := range s.Positions() {
+		got = append(got, pos)
+	}
+	want := []int{1, 2, 5}
+	if len(got) != len(want) {
+		t.Fatalf("Positions() = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Positions() = %v; want %v", got, want)
+		}
+	}
+}
+
+func TestSelect0(t *testing.T) {
+	r := rand.New(rand.NewSource(28))
+	for _, n := range []int{0, 1, 63, 64, 65, 1000, 2049} {
+		input := make([]bool, n)
+		for i := range input {
+			input[i] = r.Intn(3) != 0
+		}
+		s := NewSuccincter(input, func(b bool) bool { return b })
+
+		zeros := 0
+		for i, v := range input {
+			if !v {
+				zeros++
+				if got := s.Select0(zeros); got != i {
+					t.Fatalf("n=%d: Select0(%d) = %d; want %d", n, zeros, got, i)
+				}
+			}
+		}
+		if got := s.Select0(zeros + 1); got != -1 {
+			t.Errorf("n=%d: Select0(%d) = %d; want -1", n, zeros+1, got)
+		}
+		if got := s.Select0(0); got != -1 {
+			t.Errorf("n=%d: Select0(0) = %d; want -1", n, got)
+		}
+	}
+}
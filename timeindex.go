@@ -0,0 +1,101 @@
+package succincter
+
+import "time"
+
+// TimeIndex maps timestamps to row positions for records sorted by time, with no
+// assumption of fixed sampling. Timestamps are stored as nanosecond offsets from the
+// earliest one in an Elias-Fano sequence, typically a few bytes per record.
+//
+// Use Range to turn a time window into a half-open position range, or CountIn and
+// SelectIn to query any RankSelector over the same rows by time directly.
+type TimeIndex struct {
+	ef   *EFSequence
+	base int64 // UnixNano of the earliest timestamp
+}
+
+// NewTimeIndex builds a TimeIndex over timestamp(item) for every item. Timestamps must
+// be non-decreasing and representable as Unix nanoseconds (years 1678 to 2262).
+// timestamp is called once per item, plus once more for the first and last items.
+// Panics if timestamps decrease.
+func NewTimeIndex[T any](items []T, timestamp func(T) time.Time) *TimeIndex {
+	tx := &TimeIndex{}
+	if len(items) == 0 {
+		tx.ef = NewEFSequence(nil)
+		return tx
+	}
+
+	tx.base = timestamp(items[0]).UnixNano()
+	last := timestamp(items[len(items)-1]).UnixNano()
+	if last < tx.base {
+		panic("succincter: TimeIndex timestamps must be non-decreasing")
+	}
+
+	b := newEliasFanoBuilder(len(items), uint64(last-tx.base))
+	for _, item := range items {
+		ns := timestamp(item).UnixNano()
+		if ns < tx.base {
+			panic("succincter: TimeIndex timestamps must be non-decreasing")
+		}
+		b.push(uint64(ns - tx.base))
+	}
+	tx.ef = b.build()
+	return tx
+}
+
+// Len returns the number of indexed records.
+func (tx *TimeIndex) Len() int {
+	return tx.ef.Len()
+}
+
+// Time returns the timestamp at pos, in UTC. Panics if pos is outside [0, Len()).
+func (tx *TimeIndex) Time(pos int) time.Time {
+	if pos < 0 || pos >= tx.ef.Len() {
+		panic("succincter: TimeIndex position out of range")
+	}
+	return time.Unix(0, tx.base+int64(tx.ef.Access(pos))).UTC()
+}
+
+// Position returns the first position whose timestamp is at or after t, or Len()
+// if every timestamp is before t.
+func (tx *TimeIndex) Position(t time.Time) int {
+	ns := t.UnixNano()
+	if ns <= tx.base {
+		return 0
+	}
+	i, _ := tx.ef.NextGEQ(uint64(ns - tx.base))
+	if i < 0 {
+		return tx.ef.Len()
+	}
+	return i
+}
+
+// Range returns the half-open position range [lo, hi) of records with
+// from <= timestamp < to.
+func (tx *TimeIndex) Range(from, to time.Time) (lo, hi int) {
+	lo, hi = tx.Position(from), tx.Position(to)
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// CountIn returns how many 1-bits rs has among records with from <= timestamp < to.
+// rs must index the same rows as the TimeIndex.
+func (tx *TimeIndex) CountIn(rs RankSelector, from, to time.Time) int {
+	lo, hi := tx.Range(from, to)
+	return rs.Rank(hi) - rs.Rank(lo)
+}
+
+// SelectIn returns the position of the k-th (1-indexed) 1-bit of rs among records
+// with from <= timestamp < to, or -1 if there are fewer than k.
+func (tx *TimeIndex) SelectIn(rs RankSelector, from, to time.Time, k int) int {
+	lo, hi := tx.Range(from, to)
+	if k <= 0 {
+		return -1
+	}
+	pos := rs.Select(rs.Rank(lo) + k)
+	if pos == -1 || pos >= hi {
+		return -1
+	}
+	return pos
+}
@@ -0,0 +1,111 @@
+package succincter
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	At    time.Time
+	Error bool
+}
+
+func newTestEvents(n int) []testEvent {
+	rng := rand.New(rand.NewSource(30))
+	at := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	events := make([]testEvent, n)
+	for i := range events {
+		// Irregular gaps, including bursts with identical timestamps.
+		if rng.Intn(4) != 0 {
+			at = at.Add(time.Duration(rng.Intn(120_000)) * time.Millisecond)
+		}
+		events[i] = testEvent{At: at, Error: rng.Intn(10) == 0}
+	}
+	return events
+}
+
+func TestTimeIndexPositions(t *testing.T) {
+	events := newTestEvents(5000)
+	tx := NewTimeIndex(events, func(e testEvent) time.Time { return e.At })
+
+	if tx.Len() != len(events) {
+		t.Fatalf("Len() = %d; want %d", tx.Len(), len(events))
+	}
+	for i, e := range events {
+		if got := tx.Time(i); !got.Equal(e.At) {
+			t.Fatalf("Time(%d) = %v; want %v", i, got, e.At)
+		}
+	}
+
+	firstAtOrAfter := func(at time.Time) int {
+		for i, e := range events {
+			if !e.At.Before(at) {
+				return i
+			}
+		}
+		return len(events)
+	}
+	probes := []time.Time{
+		events[0].At.Add(-time.Hour),
+		events[0].At,
+		events[len(events)-1].At,
+		events[len(events)-1].At.Add(time.Nanosecond),
+	}
+	for i := 0; i < len(events); i += 97 {
+		probes = append(probes, events[i].At, events[i].At.Add(time.Millisecond), events[i].At.Add(-time.Nanosecond))
+	}
+	for _, at := range probes {
+		if got, want := tx.Position(at), firstAtOrAfter(at); got != want {
+			t.Fatalf("Position(%v) = %d; want %d", at, got, want)
+		}
+	}
+}
+
+func TestTimeIndexCountAndSelectIn(t *testing.T) {
+	events := newTestEvents(5000)
+	tx := NewTimeIndex(events, func(e testEvent) time.Time { return e.At })
+	errors := NewSuccincter(events, func(e testEvent) bool { return e.Error })
+
+	from := events[0].At.Add(14 * time.Hour)
+	to := from.Add(time.Hour)
+	var want []int
+	for i, e := range events {
+		if e.Error && !e.At.Before(from) && e.At.Before(to) {
+			want = append(want, i)
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("test window contains no errors")
+	}
+
+	if got := tx.CountIn(errors, from, to); got != len(want) {
+		t.Errorf("CountIn = %d; want %d", got, len(want))
+	}
+	for k := 1; k <= len(want); k++ {
+		if got := tx.SelectIn(errors, from, to, k); got != want[k-1] {
+			t.Errorf("SelectIn(k=%d) = %d; want %d", k, got, want[k-1])
+		}
+	}
+	if got := tx.SelectIn(errors, from, to, len(want)+1); got != -1 {
+		t.Errorf("SelectIn past window = %d; want -1", got)
+	}
+	if lo, hi := tx.Range(to, from); lo != hi {
+		t.Errorf("Range(to, from) = [%d, %d); want empty", lo, hi)
+	}
+}
+
+func TestTimeIndexEmptyAndUnsorted(t *testing.T) {
+	empty := NewTimeIndex([]testEvent{}, func(e testEvent) time.Time { return e.At })
+	if empty.Len() != 0 || empty.Position(time.Now()) != 0 {
+		t.Error("empty TimeIndex returned positions")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTimeIndex on decreasing timestamps did not panic")
+		}
+	}()
+	now := time.Now()
+	NewTimeIndex([]time.Time{now, now.Add(-time.Second), now.Add(time.Second)}, func(t time.Time) time.Time { return t })
+}
@@ -0,0 +1,108 @@
+package succincter
+
+// WindowCounts returns the number of 1-bits in each window [start, start+window) for
+// start = 0, step, 2*step, ... while the window fits within Len(). Each count is a
+// difference of two O(1) Ranks, so the whole call is O(Len()/step).
+// Returns nil if window or step is not positive or window exceeds Len().
+func (s *Succincter) WindowCounts(window, step int) []int {
+	if window <= 0 || step <= 0 || window > s.length {
+		return nil
+	}
+	counts := make([]int, 0, (s.length-window)/step+1)
+	for start := 0; start+window <= s.length; start += step {
+		counts = append(counts, s.Rank(start+window)-s.Rank(start))
+	}
+	return counts
+}
+
+// MaxDensityWindow returns the start of a window of the given width with the most
+// 1-bits, and that count. Returns (-1, 0) if width is not positive or exceeds Len().
+//
+// A window that starts on a 0-bit can slide right without losing a 1-bit, so only
+// windows starting on a 1-bit (or the last window) are candidates, making the call
+// O(Ones()) instead of O(Len()). Among tied windows, the leftmost candidate wins.
+func (s *Succincter) MaxDensityWindow(width int) (start, count int) {
+	if width <= 0 || width > s.length {
+		return -1, 0
+	}
+	last := s.length - width
+	start, count = 0, s.Rank(width)
+	for pos := range s.Positions() {
+		candidate := min(pos, last)
+		if c := s.Rank(candidate+width) - s.Rank(candidate); c > count {
+			start, count = candidate, c
+		}
+		if pos >= last {
+			break
+		}
+	}
+	return start, count
+}
+
+// WindowsAbove returns the regions covered by windows of the given width holding at
+// least threshold 1-bits, as sorted, disjoint half-open [start, end) intervals.
+// Overlapping and adjacent qualifying windows are merged into one region.
+//
+// The count of a sliding window only changes where a 1-bit enters or leaves it, so
+// the sweep visits each 1-bit twice and never walks the 0-bits: O(Ones()).
+// Returns nil if width is not positive or exceeds Len().
+func (s *Succincter) WindowsAbove(width, threshold int) [][2]int {
+	if width <= 0 || width > s.length {
+		return nil
+	}
+	last := s.length - width
+	if threshold <= 0 {
+		return [][2]int{{0, s.length}}
+	}
+
+	var regions [][2]int
+	emit := func(lo, hi int) { // window starts [lo, hi) qualify
+		if lo >= hi {
+			return
+		}
+		if n := len(regions); n > 0 && regions[n-1][1] >= lo {
+			regions[n-1][1] = hi - 1 + width
+			return
+		}
+		regions = append(regions, [2]int{lo, hi - 1 + width})
+	}
+
+	// count is the number of 1-bits in the window starting at start. It changes
+	// only at starts where a 1-bit leaves (leave+1) or enters (enter-width+1).
+	count, start, runStart := s.Rank(width), 0, -1
+	leave, enter := s.NextOne(0), s.NextOne(width)
+	for {
+		next := last + 1
+		if leave != -1 {
+			next = min(next, leave+1)
+		}
+		if enter != -1 {
+			next = min(next, enter-width+1)
+		}
+
+		// Every start in [start, next) has the same count.
+		if count >= threshold && runStart == -1 {
+			runStart = start
+		} else if count < threshold && runStart != -1 {
+			emit(runStart, start)
+			runStart = -1
+		}
+		if next > last {
+			break
+		}
+
+		start = next
+		for leave != -1 && leave+1 == start {
+			count--
+			leave = s.NextOne(leave + 1)
+		}
+		for enter != -1 && enter-width+1 == start {
+			count++
+			enter = s.NextOne(enter + 1)
+		}
+	}
+	if runStart != -1 {
+		emit(runStart, last+1)
+	}
+	return regions
+}
@@ -0,0 +1,118 @@
+package succincter
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func bruteWindowCount(input []bool, start, width int) int {
+	count := 0
+	for _, v := range input[start : start+width] {
+		if v {
+			count++
+		}
+	}
+	return count
+}
+
+func clusteredBools(rng *rand.Rand, n int) []bool {
+	input := make([]bool, n)
+	for i := range input {
+		p := 20
+		if (i/300)%4 == 1 {
+			p = 2 // dense stretch
+		}
+		input[i] = rng.Intn(p) == 0
+	}
+	return input
+}
+
+func TestWindowCounts(t *testing.T) {
+	rng := rand.New(rand.NewSource(31))
+	input := clusteredBools(rng, 2000)
+	s := FromBools(input)
+
+	for _, tc := range [][2]int{{1, 1}, {100, 50}, {1000, 500}, {64, 64}, {2000, 1}, {7, 300}} {
+		window, step := tc[0], tc[1]
+		var want []int
+		for start := 0; start+window <= len(input); start += step {
+			want = append(want, bruteWindowCount(input, start, window))
+		}
+		if got := s.WindowCounts(window, step); !slices.Equal(got, want) {
+			t.Errorf("WindowCounts(%d, %d) differs from brute force", window, step)
+		}
+	}
+	if s.WindowCounts(0, 1) != nil || s.WindowCounts(10, 0) != nil || s.WindowCounts(2001, 1) != nil {
+		t.Error("WindowCounts with invalid arguments returned counts")
+	}
+}
+
+func TestMaxDensityWindow(t *testing.T) {
+	rng := rand.New(rand.NewSource(32))
+	for trial := 0; trial < 20; trial++ {
+		input := clusteredBools(rng, 500+rng.Intn(1500))
+		s := FromBools(input)
+		width := 1 + rng.Intn(len(input))
+
+		best := 0
+		for start := 0; start+width <= len(input); start++ {
+			best = max(best, bruteWindowCount(input, start, width))
+		}
+		start, count := s.MaxDensityWindow(width)
+		if count != best {
+			t.Fatalf("MaxDensityWindow(%d) count = %d; want %d", width, count, best)
+		}
+		if got := bruteWindowCount(input, start, width); got != count {
+			t.Fatalf("MaxDensityWindow(%d) start %d holds %d; reported %d", width, start, got, count)
+		}
+	}
+
+	if start, count := FromBools(make([]bool, 10)).MaxDensityWindow(4); start != 0 || count != 0 {
+		t.Errorf("all-zero MaxDensityWindow = (%d, %d); want (0, 0)", start, count)
+	}
+	if start, _ := FromBools(make([]bool, 10)).MaxDensityWindow(11); start != -1 {
+		t.Errorf("MaxDensityWindow wider than input = %d; want -1", start)
+	}
+}
+
+func TestWindowsAbove(t *testing.T) {
+	rng := rand.New(rand.NewSource(33))
+	for trial := 0; trial < 30; trial++ {
+		input := clusteredBools(rng, 200+rng.Intn(2000))
+		s := FromBools(input)
+		width := 1 + rng.Intn(200)
+		if width > len(input) {
+			width = len(input)
+		}
+		threshold := rng.Intn(width/2 + 2)
+
+		// Brute force: mark every position covered by a qualifying window, then
+		// collect maximal covered runs.
+		covered := make([]bool, len(input))
+		for start := 0; start+width <= len(input); start++ {
+			if bruteWindowCount(input, start, width) >= threshold {
+				for i := start; i < start+width; i++ {
+					covered[i] = true
+				}
+			}
+		}
+		var want [][2]int
+		for i := 0; i < len(covered); i++ {
+			if covered[i] {
+				j := i
+				for j < len(covered) && covered[j] {
+					j++
+				}
+				want = append(want, [2]int{i, j})
+				i = j
+			}
+		}
+
+		got := s.WindowsAbove(width, threshold)
+		if !reflect.DeepEqual(got, want) && !(len(got) == 0 && len(want) == 0) {
+			t.Fatalf("WindowsAbove(%d, %d) = %v; want %v", width, threshold, got, want)
+		}
+	}
+}